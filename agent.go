@@ -0,0 +1,189 @@
+package structexplorer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// Operations of the agent protocol, see AgentHandler.
+const (
+	agentRoots    = "roots"    // labels and types of the explored values
+	agentFields   = "fields"   // fields of the value at a path expression
+	agentNavigate = "navigate" // fields of the value of a field, given the path of its owner and its key
+	agentEdit     = "edit"     // change the value at a path expression
+)
+
+type (
+	// agentRequest is the JSON body of a POST request to an agent.
+	agentRequest struct {
+		Op   string `json:"op"`
+		Path string `json:"path,omitempty"`
+		// key of a field of the value at Path, for navigate
+		Key string `json:"key,omitempty"`
+		// new value, for edit: text for strings, numbers, bools and durations, JSON for other types
+		Value string `json:"value,omitempty"`
+		// how fields are listed, see objectAccess
+		HideZeros bool   `json:"hideZeros,omitempty"`
+		SortBy    string `json:"sortBy,omitempty"`
+		Filter    string `json:"filter,omitempty"`
+	}
+	agentResponse struct {
+		Roots []agentRoot `json:"roots,omitempty"`
+		// whether the edit operation is allowed, for roots
+		Editable bool       `json:"editable,omitempty"`
+		Node     *agentNode `json:"node,omitempty"`
+		Error    string     `json:"error,omitempty"`
+	}
+	agentRoot struct {
+		Label string `json:"label"`
+		Type  string `json:"type"`
+	}
+	agentNode struct {
+		Label string `json:"label"`
+		// path expression to request the fields again
+		Path       string       `json:"path"`
+		Type       string       `json:"type"`
+		Length     int          `json:"length,omitempty"`
+		HasZeros   bool         `json:"hasZeros,omitempty"`
+		TotalCount int          `json:"totalCount"` // number of fields before filtering
+		Fields     []agentField `json:"fields"`
+	}
+	agentField struct {
+		Label string `json:"label"`
+		Key   string `json:"key"`
+		Path  string `json:"path"`
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+)
+
+// StartAgent listens and serves the agent protocol instead of the page; it accepts 0 or 1 Options.
+// Use the viewer of the structexplorer command to explore the values of one or more agents.
+// It listens on all network interfaces; values can only be changed if Options.AllowEdit is set.
+func (s *service) StartAgent(opts ...Options) {
+	if len(opts) > 0 {
+		s.explorer.options = &opts[0]
+	}
+	port := s.explorer.options.httpPort()
+	serveMux := s.explorer.options.serveMux()
+	rootPath := s.explorer.options.rootPath()
+	slog.Info(fmt.Sprintf("starting go struct explorer agent at http://localhost:%d%s on %v", port, rootPath, s.explorer.rootKeys()))
+	serveMux.Handle(rootPath, s.AgentHandler())
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), serveMux); err != nil {
+		slog.Error("[structexplorer] failed to start agent", "err", err)
+	}
+}
+
+// AgentHandler returns the handler of the agent protocol: a POST with a JSON request
+// with the operation "roots", "fields", "navigate" or "edit" is answered with a JSON response.
+// It accepts 0 or 1 Options; the edit operation is rejected unless Options.AllowEdit is set.
+func (s *service) AgentHandler(opts ...Options) http.Handler {
+	if len(opts) > 0 {
+		s.explorer.options = &opts[0]
+	}
+	return http.HandlerFunc(s.serveAgent)
+}
+
+func (s *service) serveAgent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST a JSON request", http.StatusMethodNotAllowed)
+		return
+	}
+	var req agentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Error("[structexplorer] invalid agent request", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := s.agentResponse(req)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("[structexplorer] failed to write agent response", "err", err)
+	}
+}
+
+func (s *service) agentResponse(req agentRequest) (resp agentResponse, err error) {
+	switch req.Op {
+	case agentRoots:
+		defer s.protect()()
+		for _, label := range s.explorer.rootLabels() {
+			root, _, _, _ := s.explorer.rootAccessWithLabel(label)
+			resp.Roots = append(resp.Roots, agentRoot{Label: label, Type: root.typeName})
+		}
+		resp.Editable = s.explorer.options.AllowEdit
+		return
+	case agentEdit:
+		if !s.explorer.options.AllowEdit {
+			return resp, errors.New("editing is not allowed, see Options.AllowEdit")
+		}
+		defer s.writeValues()()
+		defer s.protect()()
+		return resp, s.explorer.setValueAtPath(req.Path, req.Value)
+	case agentFields, agentNavigate:
+	default:
+		return resp, fmt.Errorf("unknown operation %q, use one of roots, fields, navigate or edit", req.Op)
+	}
+	if _, err := entryFilter(req.Filter); err != nil {
+		return resp, err
+	}
+	if req.SortBy != "" && !isSortBy(req.SortBy) {
+		return resp, fmt.Errorf("invalid sort %q, use key, value or type", req.SortBy)
+	}
+	// the fields are read outside the explorer lock, but not while a value is changed
+	defer s.readValues()()
+	access, err := func() (objectAccess, error) {
+		defer s.protect()()
+		oa, _, _, err := s.explorer.accessForPath(req.Path)
+		return oa, err
+	}()
+	if err != nil {
+		return resp, err
+	}
+	if req.Op == agentNavigate {
+		var ok bool
+		access, ok = func() (objectAccess, bool) {
+			defer access.lock()()
+			return access.child(req.Key)
+		}()
+		if !ok {
+			return resp, fmt.Errorf("cannot explore %s of %s", req.Key, req.Path)
+		}
+	}
+	access.hideZeros = req.HideZeros
+	access.sortBy = req.SortBy
+	access.filter = req.Filter
+	resp.Node = newAgentNode(access)
+	return
+}
+
+// newAgentNode returns the fields of the value, built the same way as a cell of the page.
+func newAgentNode(access objectAccess) *agentNode {
+	b := newIndexDataBuilder()
+	b.buildCell(cellSnapshot{access: access})
+	cell := b.data.Rows[0].Cells[0]
+	node := &agentNode{
+		Label:      cell.Name,
+		Path:       cell.ExplorePath,
+		Type:       cell.Type,
+		Length:     cell.Length,
+		HasZeros:   cell.HasZeros,
+		TotalCount: cell.TotalCount,
+		Fields:     []agentField{},
+	}
+	for _, each := range cell.Fields {
+		node.Fields = append(node.Fields, agentField{
+			Label: each.Label,
+			Key:   each.Key,
+			Path:  each.Path,
+			Type:  each.Type,
+			Value: each.ValueString,
+		})
+	}
+	return node
+}
@@ -0,0 +1,137 @@
+package structexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func postAgent(t *testing.T, h http.Handler, body string) agentResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	h.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	var resp agentResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestAgent(t *testing.T) {
+	server := &editedServer{Name: "api", Limits: make([]float64, 60)}
+	h := NewService("server", server).AgentHandler(Options{AllowEdit: true})
+
+	resp := postAgent(t, h, `{"op":"roots"}`)
+	if got, want := len(resp.Roots), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := resp.Editable, true; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := resp.Roots[0].Type, "*structexplorer.editedServer"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	resp = postAgent(t, h, `{"op":"fields","path":"server","hideZeros":true,"filter":"Limits"}`)
+	if got, want := len(resp.Node.Fields), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T] %v", got, want, resp.Node.Fields)
+	}
+	if got, want := resp.Node.Fields[0].Path, "server.Limits"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// a range of a large slice can be requested again by its path
+	resp = postAgent(t, h, `{"op":"navigate","path":"server.Limits","key":"50:60"}`)
+	if resp.Error != "" {
+		t.Fatal(resp.Error)
+	}
+	rangePath := resp.Node.Path
+	resp = postAgent(t, h, `{"op":"fields","path":"`+rangePath+`"}`)
+	if got, want := len(resp.Node.Fields), 10; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T] %s", got, want, rangePath)
+	}
+
+	resp = postAgent(t, h, `{"op":"edit","path":"server.Limits[55]","value":"1.5"}`)
+	if resp.Error != "" {
+		t.Fatal(resp.Error)
+	}
+	if got, want := server.Limits[55], 1.5; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestAgentEditNotAllowed(t *testing.T) {
+	server := &editedServer{Name: "api"}
+	h := NewService("server", server).AgentHandler()
+	if postAgent(t, h, `{"op":"roots"}`).Editable {
+		t.Error("must not be editable by default")
+	}
+	resp := postAgent(t, h, `{"op":"edit","path":"server.Name","value":"web"}`)
+	if got, want := resp.Error, "editing is not allowed, see Options.AllowEdit"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := server.Name, "api"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestAgentConcurrentFieldsAndEdit(t *testing.T) {
+	server := &editedServer{Tags: map[string]string{}}
+	for i := 0; i < 100; i++ {
+		server.Tags[fmt.Sprintf("k%d", i)] = "v"
+	}
+	h := NewService("server", server).AgentHandler(Options{AllowEdit: true})
+	var wg sync.WaitGroup
+	for _, each := range []string{
+		`{"op":"fields","path":"server.Tags"}`,
+		`{"op":"navigate","path":"server.Tags","key":"0:50"}`,
+		`{"op":"edit","path":"server.Tags[\"k7\"]","value":"w"}`,
+	} {
+		wg.Add(1)
+		go func(body string) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				rec := httptest.NewRecorder()
+				req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+				h.ServeHTTP(rec, req)
+				if strings.Contains(rec.Body.String(), `"error"`) {
+					t.Errorf("%s: %s", body, rec.Body.String())
+					return
+				}
+			}
+		}(each)
+	}
+	wg.Wait()
+	if got, want := server.Tags["k7"], "w"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestAgentErrors(t *testing.T) {
+	h := NewService("server", &editedServer{}).AgentHandler(Options{AllowEdit: true})
+	for _, each := range []string{
+		`{"op":"delete"}`,
+		`{"op":"fields","path":"client"}`,
+		`{"op":"fields","path":"server","sortBy":"size"}`,
+		`{"op":"navigate","path":"server","key":"Name"}`,
+		`{"op":"edit","path":"server","value":"1"}`,
+	} {
+		if resp := postAgent(t, h, each); resp.Error == "" {
+			t.Errorf("%s: error expected", each)
+		}
+	}
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusMethodNotAllowed; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
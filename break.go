@@ -0,0 +1,336 @@
+package structexplorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// breaks is the broker shared by all goroutines that call Break.
+var breaks = newBreakBroker()
+
+// breakBroker serves all goroutines that are paused by Break on one shared HTTP server.
+type breakBroker struct {
+	mutex    sync.Mutex
+	sessions map[int]*breakSession // id -> session
+	seq      int
+	options  *Options // replaced by ensureStarted, read while locked
+	server   *http.Server
+	listener net.Listener
+	mux      *http.ServeMux    // set when serving on the ServeMux of the options instead of listening
+	mounted  map[mountKey]bool // handlers registered on a ServeMux, which cannot be removed
+}
+
+// mountKey identifies a pattern registered on a ServeMux.
+type mountKey struct {
+	mux     *http.ServeMux
+	pattern string
+}
+
+// breakSession is one paused goroutine.
+type breakSession struct {
+	id          int
+	goroutineID int64
+	caller      string // file:line of the Break call
+	labels      []string
+	stack       []stackFrame // innermost first
+	service     *service
+	resumed     chan struct{}
+	timeout     time.Duration // zero means no timeout
+	deadline    time.Time
+	timer       *time.Timer // resumes the session at the deadline
+}
+
+func newBreakBroker() *breakBroker {
+	return &breakBroker{
+		sessions: map[int]*breakSession{},
+		options:  new(Options),
+		mounted:  map[mountKey]bool{},
+	}
+}
+
+// pause registers a session for the service and blocks until that session is resumed.
+func (b *breakBroker) pause(s *service, caller string, opts *Options) {
+	// skip pause and the Break function
+	session := b.register(s, caller, captureStack(2))
+	if err := b.ensureStarted(opts); err != nil {
+		slog.Error("[structexplorer] failed to start break service", "err", err)
+		b.resume(session.id)
+		return
+	}
+	timeout := new(Options).breakTimeout()
+	if opts != nil {
+		timeout = opts.breakTimeout()
+	}
+	b.startTimer(session.id, timeout)
+	url := b.sessionURL(session.id)
+	slog.Info("[structexplorer] paused goroutine, open the explorer to resume", "url", url, "caller", caller, "timeout", timeout)
+	if err := open(url); err != nil {
+		slog.Warn("[structexplorer] failed to open browser", "url", url, "err", err)
+	}
+	// this blocks until the session is resumed.
+	<-session.resumed
+}
+
+// startTimer resumes the session automatically after the timeout, if set.
+func (b *breakBroker) startTimer(id int, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	session, ok := b.sessions[id]
+	if !ok {
+		return
+	}
+	session.timeout = timeout
+	session.deadline = time.Now().Add(timeout)
+	session.timer = time.AfterFunc(timeout, func() {
+		slog.Info("[structexplorer] break timeout expired, resuming", "caller", session.caller)
+		b.resume(id)
+	})
+}
+
+// extend moves the deadline of the session by its timeout.
+func (b *breakBroker) extend(id int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	session, ok := b.sessions[id]
+	if !ok || session.timer == nil {
+		return
+	}
+	session.deadline = session.deadline.Add(session.timeout)
+	session.timer.Reset(time.Until(session.deadline))
+}
+
+func (b *breakBroker) register(s *service, caller string, stack []stackFrame) *breakSession {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.seq++
+	session := &breakSession{
+		id:          b.seq,
+		goroutineID: currentGoroutineID(),
+		caller:      caller,
+		stack:       stack,
+		labels:      s.explorer.rootKeys(),
+		service:     s,
+		resumed:     make(chan struct{}),
+	}
+	sort.Strings(session.labels)
+	s.session = session
+	b.sessions[session.id] = session
+	return session
+}
+
+// ensureStarted starts listening unless it already does.
+// If the options have a ServeMux then the broker is served by it instead.
+// The options are only used when the server is not yet running.
+func (b *breakBroker) ensureStarted(opts *Options) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.server != nil || b.mux != nil {
+		return nil
+	}
+	if opts != nil {
+		b.options = opts
+	}
+	if mux := b.options.ServeMux; mux != nil {
+		return b.mountOn(mux)
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", b.options.httpPort()))
+	if err != nil {
+		return err
+	}
+	b.listener = listener
+	b.server = &http.Server{Handler: b}
+	go b.server.Serve(listener)
+	return nil
+}
+
+// mountOn registers the broker on the ServeMux at the root path, once for each ServeMux and path.
+// pre: locked
+func (b *breakBroker) mountOn(mux *http.ServeMux) error {
+	pattern := b.options.rootPath()
+	if !strings.HasSuffix(pattern, "/") {
+		pattern += "/"
+	}
+	key := mountKey{mux: mux, pattern: pattern}
+	if !b.mounted[key] {
+		if _, existing := mux.Handler(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: pattern}}); existing == pattern {
+			return fmt.Errorf("path %s is already handled by the ServeMux, use another HTTPBasePath", pattern)
+		}
+		mux.Handle(pattern, b)
+		b.mounted[key] = true
+	}
+	b.mux = mux
+	return nil
+}
+
+// resume unblocks the goroutine of the session.
+// The server stops when no more goroutines are paused.
+func (b *breakBroker) resume(id int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.resumeLocked(id)
+}
+
+func (b *breakBroker) resumeAll() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for id := range b.sessions {
+		b.resumeLocked(id)
+	}
+}
+
+func (b *breakBroker) resumeLocked(id int) {
+	session, ok := b.sessions[id]
+	if !ok {
+		return
+	}
+	delete(b.sessions, id)
+	if session.timer != nil {
+		session.timer.Stop()
+	}
+	close(session.resumed)
+	if len(b.sessions) > 0 {
+		return
+	}
+	// a handler on a ServeMux stays registered and serves that no goroutines are paused.
+	b.mux = nil
+	if b.server == nil {
+		return
+	}
+	// free the port now; let running requests finish in the background.
+	b.listener.Close()
+	go b.server.Shutdown(context.Background())
+	b.server = nil
+	b.listener = nil
+}
+
+// rootPath returns the root path of the options.
+func (b *breakBroker) rootPath() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.options.rootPath()
+}
+
+func (b *breakBroker) sessionPath(id int) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.sessionPathLocked(id)
+}
+
+// pre: locked
+func (b *breakBroker) sessionPathLocked(id int) string {
+	return path.Join(b.options.rootPath(), strconv.Itoa(id))
+}
+
+func (b *breakBroker) sessionURL(id int) string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return fmt.Sprintf("http://localhost:%d%s", b.options.httpPort(), b.sessionPathLocked(id))
+}
+
+// breakEntries returns the paused goroutines ordered by session id.
+func (b *breakBroker) breakEntries(currentID int) (list []breakEntry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	ids := []int{}
+	for id := range b.sessions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		each := b.sessions[id]
+		list = append(list, breakEntry{
+			Path:        b.sessionPathLocked(each.id),
+			GoroutineID: each.goroutineID,
+			Caller:      each.caller,
+			Labels:      strings.Join(each.labels, ","),
+			IsCurrent:   each.id == currentID,
+			Deadline:    deadlineMillis(each.deadline),
+		})
+	}
+	return
+}
+
+// ServeHTTP implements http.Handler
+// The root path redirects to the first paused goroutine;
+// the path <root>/<id> is served by the explorer of that session.
+func (b *breakBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	root := b.rootPath()
+	rel := strings.Trim(strings.TrimPrefix(r.URL.Path, root), "/")
+	if rel == "" {
+		b.serveFirst(w, r)
+		return
+	}
+	id, err := strconv.Atoi(rel)
+	if err != nil {
+		http.Error(w, "[structexplorer] not found", http.StatusNotFound)
+		return
+	}
+	b.mutex.Lock()
+	session, ok := b.sessions[id]
+	b.mutex.Unlock()
+	if !ok {
+		http.Redirect(w, r, root, http.StatusSeeOther)
+		return
+	}
+	session.service.ServeHTTP(w, r)
+}
+
+func (b *breakBroker) serveFirst(w http.ResponseWriter, r *http.Request) {
+	b.mutex.Lock()
+	first := 0
+	for id := range b.sessions {
+		if first == 0 || id < first {
+			first = id
+		}
+	}
+	b.mutex.Unlock()
+	if first == 0 {
+		fmt.Fprintln(w, "[structexplorer] no goroutines are paused")
+		return
+	}
+	http.Redirect(w, r, b.sessionPath(first), http.StatusSeeOther)
+}
+
+// deadlineMillis returns the Unix time in milliseconds for use in Javascript or 0 if not set.
+func deadlineMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// currentGoroutineID parses the id from the first line of the stack, e.g. "goroutine 42 [running]:".
+func currentGoroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	fields := strings.Fields(string(buf))
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseInt(fields[1], 10, 64)
+	return id
+}
+
+// callerLocation returns file:line of the code that called the function which calls callerLocation.
+func callerLocation() string {
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return "?"
+	}
+	// keep the package folder to distinguish files with the same name
+	return fmt.Sprintf("%s/%s:%d", path.Base(path.Dir(file)), path.Base(file), line)
+}
@@ -0,0 +1,122 @@
+package structexplorer
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// breakEnvName is the name of the environment variable that disables all breakpoints if set to "off", "false" or "0".
+const breakEnvName = "STRUCTEXPLORER_BREAK"
+
+// sites is the registry of all locations in code that called one of the Break functions.
+var sites = newBreakSites(os.Getenv(breakEnvName))
+
+// breakSite is a location (file:line) in code that calls one of the Break functions.
+type breakSite struct {
+	location string
+	hits     int
+	enabled  bool
+}
+
+type breakSites struct {
+	mutex    sync.Mutex
+	sites    map[string]*breakSite // location -> site
+	onceIDs  map[string]bool       // ids used by BreakOnce that did break
+	disabled bool                  // all sites
+}
+
+func newBreakSites(envValue string) *breakSites {
+	switch strings.ToLower(envValue) {
+	case "off", "false", "0":
+		return &breakSites{sites: map[string]*breakSite{}, onceIDs: map[string]bool{}, disabled: true}
+	}
+	return &breakSites{sites: map[string]*breakSite{}, onceIDs: map[string]bool{}}
+}
+
+// hit registers the site if new, counts the hit and returns whether the program must break.
+// The condition is called with the number of hits so far, including this one.
+func (b *breakSites) hit(location string, condition func(hits int) bool) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	site, ok := b.sites[location]
+	if !ok {
+		site = &breakSite{location: location, enabled: true}
+		b.sites[location] = site
+	}
+	site.hits++
+	if b.disabled || !site.enabled {
+		return false
+	}
+	return condition(site.hits)
+}
+
+// firstTime returns true the first time it is called for the id.
+// It must only be called by a condition passed to hit.
+func (b *breakSites) firstTime(id string) bool {
+	if b.onceIDs[id] {
+		return false
+	}
+	b.onceIDs[id] = true
+	return true
+}
+
+func (b *breakSites) toggle(location string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if site, ok := b.sites[location]; ok {
+		site.enabled = !site.enabled
+	}
+}
+
+func (b *breakSites) toggleAll() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.disabled = !b.disabled
+}
+
+// siteEntries returns all registered sites ordered by location.
+func (b *breakSites) siteEntries() (list []breakSiteEntry, allEnabled bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, each := range b.sites {
+		list = append(list, breakSiteEntry{
+			Location: each.location,
+			Hits:     each.hits,
+			Enabled:  each.enabled,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Location < list[j].Location
+	})
+	return list, !b.disabled
+}
+
+// BreakIf calls Break if the condition is true.
+// Like all Break functions, the call site can be disabled from the explorer page.
+func BreakIf(cond bool, keyvaluePairs ...any) {
+	location := callerLocation()
+	if !sites.hit(location, func(int) bool { return cond }) {
+		return
+	}
+	breaks.pause(NewService(keyvaluePairs...).(*service), location, nil)
+}
+
+// BreakEvery calls Break on every n-th time it is called from the same location.
+func BreakEvery(n int, keyvaluePairs ...any) {
+	location := callerLocation()
+	if !sites.hit(location, func(hits int) bool { return n > 0 && hits%n == 0 }) {
+		return
+	}
+	breaks.pause(NewService(keyvaluePairs...).(*service), location, nil)
+}
+
+// BreakOnce calls Break only the first time it is called with the id.
+func BreakOnce(id string, keyvaluePairs ...any) {
+	location := callerLocation()
+	if !sites.hit(location, func(int) bool { return sites.firstTime(id) }) {
+		return
+	}
+	breaks.pause(NewService(keyvaluePairs...).(*service), location, nil)
+}
@@ -0,0 +1,100 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreakSitesEvery(t *testing.T) {
+	b := newBreakSites("")
+	every2 := func(hits int) bool { return hits%2 == 0 }
+	if b.hit("a.go:1", every2) {
+		t.Error("first hit must not break")
+	}
+	if !b.hit("a.go:1", every2) {
+		t.Error("second hit must break")
+	}
+	list, enabled := b.siteEntries()
+	if got, want := list[0].Hits, 2; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !enabled {
+		t.Error("sites must be enabled")
+	}
+}
+
+func TestBreakSitesToggle(t *testing.T) {
+	b := newBreakSites("")
+	always := func(int) bool { return true }
+	b.hit("a.go:1", always)
+	b.toggle("a.go:1")
+	if b.hit("a.go:1", always) {
+		t.Error("disabled site must not break")
+	}
+	b.toggle("a.go:1")
+	b.toggleAll()
+	if b.hit("a.go:1", always) {
+		t.Error("disabled sites must not break")
+	}
+}
+
+func TestBreakSitesEnvironment(t *testing.T) {
+	b := newBreakSites("off")
+	if b.hit("a.go:1", func(int) bool { return true }) {
+		t.Error("disabled sites must not break")
+	}
+}
+
+func TestBreakSitesOnce(t *testing.T) {
+	b := newBreakSites("")
+	once := func(int) bool { return b.firstTime("id") }
+	if !b.hit("a.go:1", once) {
+		t.Error("first hit must break")
+	}
+	if b.hit("a.go:1", once) {
+		t.Error("second hit must not break")
+	}
+}
+
+func TestBreakIfFalse(t *testing.T) {
+	BreakIf(false, "now", time.Now())
+	list, _ := sites.siteEntries()
+	found := false
+	for _, each := range list {
+		if strings.Contains(each.Location, "/break_sites_test.go:") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("site not registered: %v", list)
+	}
+}
+
+func TestServeToggleBreakSite(t *testing.T) {
+	sites.hit("toggle.go:1", func(int) bool { return false })
+	s := NewService("now", time.Now()).(*service)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"action":"toggleBreakSite","selections":["toggle.go:1"]}`))
+	s.ServeHTTP(rec, req)
+	if sites.hit("toggle.go:1", func(int) bool { return true }) {
+		t.Error("disabled site must not break")
+	}
+}
+
+func TestServiceBreakSite(t *testing.T) {
+	old := sites
+	defer func() { sites = old }()
+	sites = newBreakSites("off")
+	// returns because all sites are disabled
+	NewService("now", time.Now()).Break()
+	list, _ := sites.siteEntries()
+	if got, want := len(list), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !strings.Contains(list[0].Location, "/break_sites_test.go:") {
+		t.Errorf("unexpected location %s", list[0].Location)
+	}
+}
@@ -0,0 +1,136 @@
+package structexplorer
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// stackFrame is one function call in the stack of a paused goroutine.
+// Its fields are exported so that a frame can be explored.
+type stackFrame struct {
+	Function string
+	File     string
+	Line     int
+	Locals   map[string]any // registered with Locals, can be nil
+}
+
+func (f stackFrame) location() string {
+	return path.Base(f.File) + ":" + strconv.Itoa(f.Line)
+}
+
+// frameLocals are the variables registered by a function in a goroutine.
+type frameLocals struct {
+	function string
+	depth    int // number of frames from the function to the bottom of the stack
+	vars     map[string]any
+}
+
+// locals holds the registered variables per goroutine id.
+var locals = struct {
+	mutex sync.Mutex
+	byID  map[int64][]*frameLocals
+}{byID: map[int64][]*frameLocals{}}
+
+// Locals registers local variables of the calling function for the current goroutine.
+// When this goroutine is paused by Break, the variables are shown with the stack frame of that function call.
+// Values are taken when Locals is called; pass a pointer, or a func() any that is called when the goroutine pauses,
+// for a variable that changes after that.
+// It returns a function to unregister them; typically used with defer.
+//
+//	defer structexplorer.Locals(map[string]any{"count": func() any { return count }, "item": &item})()
+func Locals(vars map[string]any) func() {
+	entry := &frameLocals{function: "?", vars: vars}
+	if frames := callerFrames(1); len(frames) > 0 {
+		entry.function = frames[0].Function
+		entry.depth = len(frames)
+	}
+	id := currentGoroutineID()
+	locals.mutex.Lock()
+	locals.byID[id] = append(locals.byID[id], entry)
+	locals.mutex.Unlock()
+	return func() {
+		locals.mutex.Lock()
+		defer locals.mutex.Unlock()
+		list := locals.byID[id]
+		for i, each := range list {
+			if each == entry {
+				list = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(locals.byID, id)
+		} else {
+			locals.byID[id] = list
+		}
+	}
+}
+
+// captureStack returns the frames of the current goroutine, innermost first.
+// The skip value 0 identifies the caller of captureStack.
+// Locals registered by function calls on the stack are added to their frames, matched by function and depth
+// such that each call of a recursive function has its own.
+func captureStack(skip int) (list []stackFrame) {
+	frames := callerFrames(skip + 1)
+	locals.mutex.Lock()
+	registered := locals.byID[currentGoroutineID()]
+	locals.mutex.Unlock()
+	for i, frame := range frames {
+		if strings.HasPrefix(frame.Function, "runtime.") {
+			continue
+		}
+		list = append(list, stackFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			Locals:   localsAt(registered, frame.Function, len(frames)-i),
+		})
+	}
+	return
+}
+
+// localsAt returns the variables registered by the function call at the depth, or nil if none.
+// Values that are a func() any are replaced by their result.
+func localsAt(registered []*frameLocals, function string, depth int) (vars map[string]any) {
+	for _, each := range registered {
+		if each.depth != depth || each.function != function {
+			continue
+		}
+		if vars == nil {
+			vars = map[string]any{}
+		}
+		for k, v := range each.vars {
+			if f, ok := v.(func() any); ok {
+				v = f()
+			}
+			vars[k] = v
+		}
+	}
+	return
+}
+
+// callerFrames returns all frames of the current goroutine, innermost first, including those of the runtime
+// such that their number is the depth of the first frame. The skip value 0 identifies the caller of callerFrames.
+func callerFrames(skip int) (list []runtime.Frame) {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(skip+2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		list = append(list, frame)
+		if !more {
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureStackWithLocals(t *testing.T) {
+	count := 42
+	defer Locals(map[string]any{"count": count})()
+	stack := stackWithInnerLocals()
+	if got, want := stack[0].Function, "github.com/emicklei/structexplorer.stackWithInnerLocals"; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := stack[0].Locals["inner"], "yes"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := stack[1].Locals["count"], 42; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func stackWithInnerLocals() []stackFrame {
+	defer Locals(map[string]any{"inner": "yes"})()
+	return captureStack(0)
+}
+
+func TestLocalsUnregister(t *testing.T) {
+	Locals(map[string]any{"a": 1})()
+	if got, want := len(captureStack(0)[0].Locals), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestExploreFrame(t *testing.T) {
+	defer Locals(map[string]any{"now": time.Now()})()
+	s := NewService("now", time.Now()).(*service)
+	session := breaks.register(s, "now.go:1", captureStack(0))
+	defer breaks.resume(session.id)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"action":"exploreFrame","selections":["0"]}`))
+	s.ServeHTTP(rec, req)
+	oa := s.explorer.objectAt(0, 1)
+	if got, want := oa.label, "#0 structexplorer.TestExploreFrame"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), "break_stack_test.go") {
+		t.Error("missing stack frame")
+	}
+}
+
+func TestCaptureStackRecursive(t *testing.T) {
+	stack := recurseWithLocals(3)
+	for i := 0; i <= 3; i++ {
+		if got, want := stack[i].Locals["level"], i; got != want {
+			t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+		}
+	}
+}
+
+func recurseWithLocals(level int) []stackFrame {
+	defer Locals(map[string]any{"level": level})()
+	if level == 0 {
+		return captureStack(0)
+	}
+	return recurseWithLocals(level - 1)
+}
+
+func TestLocalsReadWhenPaused(t *testing.T) {
+	count := 1
+	defer Locals(map[string]any{"count": func() any { return count }})()
+	count = 2
+	if got, want := captureStack(0)[0].Locals["count"], 2; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreakBrokerSessions(t *testing.T) {
+	b := newBreakBroker()
+	s1 := NewService("one", time.Now()).(*service)
+	s2 := NewService("two", time.Now()).(*service)
+	one := b.register(s1, "one.go:1", nil)
+	two := b.register(s2, "two.go:2", nil)
+
+	list := b.breakEntries(two.id)
+	if got, want := len(list), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[1].IsCurrent, true; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[0].Caller, "one.go:1"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if list[0].GoroutineID == 0 {
+		t.Error("missing goroutine id")
+	}
+
+	// root redirects to first
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	b.ServeHTTP(rec, req)
+	if got, want := rec.Header().Get("location"), "/1"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// session page
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/2", nil)
+	b.ServeHTTP(rec, req)
+	if got, want := rec.Code, 200; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	b.resume(one.id)
+	select {
+	case <-one.resumed:
+	default:
+		t.Error("session one not resumed")
+	}
+	b.resumeAll()
+	select {
+	case <-two.resumed:
+	default:
+		t.Error("session two not resumed")
+	}
+	if got, want := len(b.breakEntries(0)), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestBreakResumeInstruction(t *testing.T) {
+	s := NewService("now", time.Now()).(*service)
+	session := breaks.register(s, "now.go:1", nil)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"action":"resume"}`))
+	s.ServeHTTP(rec, req)
+	select {
+	case <-session.resumed:
+	default:
+		t.Error("session not resumed")
+	}
+}
+
+func TestCurrentGoroutineID(t *testing.T) {
+	if currentGoroutineID() == 0 {
+		t.Fail()
+	}
+}
+
+func TestBreakTimeout(t *testing.T) {
+	b := newBreakBroker()
+	session := b.register(NewService("now", time.Now()).(*service), "now.go:1", nil)
+	b.startTimer(session.id, 10*time.Millisecond)
+	b.extend(session.id)
+	if got, want := b.breakEntries(session.id)[0].Deadline, session.deadline.UnixMilli(); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	select {
+	case <-session.resumed:
+	case <-time.After(time.Second):
+		t.Error("session not resumed after timeout")
+	}
+}
+
+func TestBreakTimeoutFromEnvironment(t *testing.T) {
+	t.Setenv(breakTimeoutEnvName, "2s")
+	if got, want := new(Options).breakTimeout(), 2*time.Second; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	o := Options{BreakTimeout: time.Second}
+	if got, want := o.breakTimeout(), time.Second; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestBreakOnServeMux(t *testing.T) {
+	b := newBreakBroker()
+	mux := http.NewServeMux()
+	mux.Handle("/taken/", http.NotFoundHandler())
+	if err := b.ensureStarted(&Options{ServeMux: mux, HTTPBasePath: "/taken"}); err == nil {
+		t.Error("error expected")
+	}
+	session := b.register(NewService("now", time.Now()).(*service), "now.go:1", nil)
+	if err := b.ensureStarted(&Options{ServeMux: mux, HTTPBasePath: "/break"}); err != nil {
+		t.Fatal(err)
+	}
+	if b.server != nil {
+		t.Error("must not listen")
+	}
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/break/1", nil)
+	mux.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	b.resume(session.id)
+
+	// registered once
+	b.register(NewService("now", time.Now()).(*service), "now.go:1", nil)
+	if err := b.ensureStarted(&Options{ServeMux: mux, HTTPBasePath: "/break"}); err != nil {
+		t.Fatal(err)
+	}
+	b.resumeAll()
+}
+
+func TestBreakBrokerServeWhileStarting(t *testing.T) {
+	b := newBreakBroker()
+	mux := http.NewServeMux()
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			rec := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/starting/1", nil)
+			b.ServeHTTP(rec, req)
+		}
+		done <- true
+	}()
+	if err := b.ensureStarted(&Options{ServeMux: mux, HTTPBasePath: "/starting"}); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if got, want := b.sessionPath(1), "/starting/1"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/emicklei/structexplorer"
+)
+
+// The dlv types mirror the JSON of the API (version 2) of a headless delve server,
+// see https://github.com/go-delve/delve/tree/master/service/api.
+type (
+	dlvScope struct {
+		GoroutineID int64 // -1 for the selected goroutine
+		Frame       int
+	}
+	dlvLoadConfig struct {
+		FollowPointers     bool
+		MaxVariableRecurse int
+		MaxStringLen       int
+		MaxArrayValues     int
+		MaxStructFields    int
+	}
+	dlvVariable struct {
+		Name  string       `json:"name"`
+		Type  string       `json:"type"`
+		Kind  reflect.Kind `json:"kind"`
+		Value string       `json:"value"`
+		Len   int64        `json:"len"`
+		// fields of structs, elements of slices and arrays, pointee of pointers, value of interfaces;
+		// keys and values of maps alternate
+		Children   []dlvVariable `json:"children"`
+		Unreadable string        `json:"unreadable"`
+	}
+	dlvStateIn struct {
+		NonBlocking bool
+	}
+	dlvStateOut struct {
+		State struct {
+			Running bool
+			Exited  bool `json:"exited"`
+		}
+	}
+	dlvScopeIn struct {
+		Scope dlvScope
+		Cfg   dlvLoadConfig
+	}
+	dlvEvalIn struct {
+		Scope dlvScope
+		Expr  string
+		Cfg   *dlvLoadConfig
+	}
+	dlvEvalOut struct {
+		Variable *dlvVariable
+	}
+	dlvLocalsOut struct {
+		Variables []dlvVariable
+	}
+	dlvArgsOut struct {
+		Args []dlvVariable
+	}
+)
+
+// delveSession reads values of the process that is debugged by a headless delve server.
+type delveSession struct {
+	client *rpc.Client
+	scope  dlvScope
+	config dlvLoadConfig
+	// expressions to evaluate; if empty then the arguments and local variables of the frame are read
+	exprs []string
+}
+
+// runDelve serves the values read from a delve server, which debugs a paused process or a core file.
+func runDelve(args []string) {
+	fs := flag.NewFlagSet("delve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:2345", "address of the headless delve server")
+	port := fs.Int("port", 5656, "HTTP port of the explorer")
+	goroutine := fs.Int64("goroutine", -1, "goroutine of which the frame is read, -1 for the selected goroutine")
+	frame := fs.Int("frame", 0, "frame of the goroutine, 0 is the current function")
+	depth := fs.Int("depth", 4, "maximum number of fields between a read variable and a read value")
+	elements := fs.Int("elements", 100, "maximum number of elements of slices, arrays and maps")
+	refresh := fs.Duration("refresh", 0, "read the values again on this interval, e.g. after continuing in another delve client")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: structexplorer delve [flags] [expression...]")
+		fmt.Fprintln(os.Stderr, "e.g.   dlv exec ./app --headless --accept-multiclient --api-version=2 --listen=127.0.0.1:2345")
+		fmt.Fprintln(os.Stderr, "       dlv core ./app core.1234 --headless --api-version=2 --listen=127.0.0.1:2345")
+		fmt.Fprintln(os.Stderr, "       structexplorer delve 'cache' 'main.config'")
+		fmt.Fprintln(os.Stderr, "without expressions, the arguments and local variables of the frame are read")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	client, err := jsonrpc.Dial("tcp", *addr)
+	if err != nil {
+		slog.Error("[structexplorer] cannot connect to delve", "addr", *addr, "err", err)
+		os.Exit(1)
+	}
+	d := &delveSession{
+		client: client,
+		scope:  dlvScope{GoroutineID: *goroutine, Frame: *frame},
+		config: dlvLoadConfig{
+			FollowPointers:     true,
+			MaxVariableRecurse: *depth,
+			MaxStringLen:       256,
+			MaxArrayValues:     *elements,
+			MaxStructFields:    -1,
+		},
+		exprs: fs.Args(),
+	}
+	s := structexplorer.NewService()
+	if err := d.explore(s); err != nil {
+		slog.Error("[structexplorer] cannot read values", "addr", *addr, "err", err)
+		os.Exit(1)
+	}
+	if *refresh > 0 {
+		go func() {
+			for range time.Tick(*refresh) {
+				if err := d.explore(s); err != nil {
+					slog.Warn("[structexplorer] cannot read values again", "err", err)
+				}
+			}
+		}()
+	}
+	s.Start(structexplorer.Options{HTTPPort: *port})
+}
+
+// explore reads the values and explores them, replacing the values of a previous read.
+func (d *delveSession) explore(s structexplorer.Service) error {
+	values, err := d.read()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(values); i += 2 {
+		s.Explore(values[i].(string), values[i+1])
+	}
+	return nil
+}
+
+// read returns pairs of labels and values, to pass to Explore.
+func (d *delveSession) read() (labelValuePairs []any, err error) {
+	var state dlvStateOut
+	if err := d.client.Call("RPCServer.State", dlvStateIn{NonBlocking: true}, &state); err != nil {
+		return nil, err
+	}
+	if state.State.Exited {
+		return nil, errors.New("process has exited")
+	}
+	if state.State.Running {
+		return nil, errors.New("process is running, halt it or wait for a breakpoint")
+	}
+	if len(d.exprs) == 0 {
+		var args dlvArgsOut
+		if err := d.client.Call("RPCServer.ListFunctionArgs", dlvScopeIn{Scope: d.scope, Cfg: d.config}, &args); err != nil {
+			return nil, err
+		}
+		var locals dlvLocalsOut
+		if err := d.client.Call("RPCServer.ListLocalVars", dlvScopeIn{Scope: d.scope, Cfg: d.config}, &locals); err != nil {
+			return nil, err
+		}
+		return []any{"args", variablesMap(args.Args), "locals", variablesMap(locals.Variables)}, nil
+	}
+	// values that cannot be explored by themselves, by expression
+	scalars := map[string]any{}
+	for _, each := range d.exprs {
+		var out dlvEvalOut
+		if err := d.client.Call("RPCServer.Eval", dlvEvalIn{Scope: d.scope, Expr: each, Cfg: &d.config}, &out); err != nil {
+			return nil, fmt.Errorf("cannot evaluate %q: %w", each, err)
+		}
+		if out.Variable == nil {
+			return nil, fmt.Errorf("no value for %q", each)
+		}
+		v := delveValue(*out.Variable)
+		if k := reflect.ValueOf(v).Kind(); k != reflect.Map && k != reflect.Slice {
+			scalars[each] = v
+			continue
+		}
+		labelValuePairs = append(labelValuePairs, each, v)
+	}
+	if len(scalars) > 0 {
+		labelValuePairs = append(labelValuePairs, "values", scalars)
+	}
+	return
+}
+
+// variablesMap returns the values by name; shadowed variables get a number.
+func variablesMap(list []dlvVariable) map[string]any {
+	m := map[string]any{}
+	for _, each := range list {
+		name := each.Name
+		for n := 2; ; n++ {
+			if _, ok := m[name]; !ok {
+				break
+			}
+			name = fmt.Sprintf("%s (%d)", each.Name, n)
+		}
+		m[name] = delveValue(each)
+	}
+	return m
+}
+
+// delveValue returns a generic value for the variable: a map[string]any for a struct or map,
+// a []any for a slice or array and a bool, int64, uint64, float64, time.Duration or string otherwise.
+// Values that were not read because of the limits are strings that say so.
+func delveValue(v dlvVariable) any {
+	if v.Unreadable != "" {
+		return "unreadable: " + v.Unreadable
+	}
+	switch v.Kind {
+	case reflect.Invalid:
+		// e.g. the value of a nil interface
+		return nil
+	case reflect.Bool:
+		b, _ := strconv.ParseBool(v.Value)
+		return b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, _ := strconv.ParseInt(v.Value, 10, 64)
+		if v.Type == "time.Duration" {
+			return time.Duration(i)
+		}
+		return i
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, _ := strconv.ParseUint(v.Value, 10, 64)
+		return u
+	case reflect.Float32, reflect.Float64:
+		f, _ := strconv.ParseFloat(v.Value, 64)
+		return f
+	case reflect.String:
+		if int64(len(v.Value)) < v.Len {
+			return fmt.Sprintf("%s... (%d more bytes)", v.Value, v.Len-int64(len(v.Value)))
+		}
+		return v.Value
+	case reflect.Pointer, reflect.Interface:
+		if v.Kind == reflect.Pointer && v.Value == "0" {
+			return nil
+		}
+		if len(v.Children) == 0 {
+			return notRead(v)
+		}
+		return delveValue(v.Children[0])
+	case reflect.Struct:
+		if len(v.Children) == 0 && v.Len > 0 {
+			return notRead(v)
+		}
+		m := map[string]any{}
+		for _, each := range v.Children {
+			m[each.Name] = delveValue(each)
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if len(v.Children) == 0 && v.Len > 0 {
+			return notRead(v)
+		}
+		list := []any{}
+		for _, each := range v.Children {
+			list = append(list, delveValue(each))
+		}
+		if more := v.Len - int64(len(v.Children)); more > 0 {
+			list = append(list, fmt.Sprintf("... (%d more)", more))
+		}
+		return list
+	case reflect.Map:
+		if len(v.Children) == 0 && v.Len > 0 {
+			return notRead(v)
+		}
+		m := map[string]any{}
+		for i := 0; i+1 < len(v.Children); i += 2 {
+			m[delveKey(v.Children[i])] = delveValue(v.Children[i+1])
+		}
+		if more := v.Len - int64(len(v.Children)/2); more > 0 {
+			m["..."] = fmt.Sprintf("(%d more)", more)
+		}
+		return m
+	}
+	// e.g. channels, functions and complex numbers
+	if v.Value != "" {
+		return v.Value
+	}
+	return v.Type
+}
+
+// delveKey returns the string of a map key; keys that are not a string or number are shown by their value.
+func delveKey(v dlvVariable) string {
+	k := delveValue(v)
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprint(k)
+}
+
+func notRead(v dlvVariable) string {
+	return fmt.Sprintf("%s (not read, use a larger -depth or explore its expression)", v.Type)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeDelve answers the calls of a delveSession like a headless delve server.
+// Its methods use generic maps because net/rpc requires exported or builtin types.
+type fakeDelve struct {
+	running bool
+}
+
+func (f *fakeDelve) State(in map[string]any, out *map[string]any) error {
+	*out = map[string]any{"State": map[string]any{"Running": f.running}}
+	return nil
+}
+
+func (f *fakeDelve) ListFunctionArgs(in map[string]any, out *map[string]any) error {
+	*out = map[string]any{"Args": []dlvVariable{{Name: "n", Kind: reflect.Int, Value: "21"}}}
+	return nil
+}
+
+func (f *fakeDelve) ListLocalVars(in map[string]any, out *map[string]any) error {
+	*out = map[string]any{"Variables": []dlvVariable{
+		{Name: "err", Kind: reflect.Interface, Children: []dlvVariable{{Kind: reflect.Invalid}}},
+		{Name: "err", Kind: reflect.String, Value: "shadowed"},
+	}}
+	return nil
+}
+
+func (f *fakeDelve) Eval(in map[string]any, out *map[string]any) error {
+	var v dlvVariable
+	switch in["Expr"] {
+	case "count":
+		v = dlvVariable{Kind: reflect.Int, Value: "42"}
+	case "s":
+		v = dlvVariable{Kind: reflect.Pointer, Value: "824634", Children: []dlvVariable{
+			{Kind: reflect.Struct, Len: 1, Children: []dlvVariable{{Name: "Name", Kind: reflect.String, Value: "api", Len: 3}}},
+		}}
+	default:
+		return fmt.Errorf("could not find symbol value for %v", in["Expr"])
+	}
+	*out = map[string]any{"Variable": v}
+	return nil
+}
+
+func newFakeDelveSession(t *testing.T, fake *fakeDelve, exprs ...string) *delveSession {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("RPCServer", fake); err != nil {
+		t.Fatal(err)
+	}
+	conn, serverConn := net.Pipe()
+	go server.ServeCodec(jsonrpc.NewServerCodec(serverConn))
+	client := jsonrpc.NewClient(conn)
+	t.Cleanup(func() { client.Close() })
+	return &delveSession{client: client, scope: dlvScope{GoroutineID: -1}, exprs: exprs}
+}
+
+func TestDelveRead(t *testing.T) {
+	d := newFakeDelveSession(t, new(fakeDelve))
+	got, err := d.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []any{
+		"args", map[string]any{"n": int64(21)},
+		"locals", map[string]any{"err": nil, "err (2)": "shadowed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	d.exprs = []string{"s", "count"}
+	got, err = d.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []any{"s", map[string]any{"Name": "api"}, "values", map[string]any{"count": int64(42)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestDelveReadErrors(t *testing.T) {
+	if _, err := newFakeDelveSession(t, new(fakeDelve), "nosuch").read(); err == nil {
+		t.Error("error expected for unknown symbol")
+	}
+	if _, err := newFakeDelveSession(t, &fakeDelve{running: true}).read(); err == nil {
+		t.Error("error expected for running process")
+	}
+}
+
+func TestDelveValue(t *testing.T) {
+	// as read by delve with a MaxVariableRecurse of 1 and MaxArrayValues of 2
+	var v dlvVariable
+	if err := json.Unmarshal([]byte(`{"type":"main.Server","kind":25,"len":6,"children":[
+		{"name":"Timeout","type":"time.Duration","kind":6,"value":"1000000000"},
+		{"name":"Ratio","kind":14,"value":"0.5"},
+		{"name":"Big","type":"[]int","kind":23,"len":200,"children":[{"kind":2,"value":"1"},{"kind":2,"value":"2"}]},
+		{"name":"Tags","kind":21,"len":1,"children":[{"kind":24,"value":"a","len":1},{"kind":2,"value":"1"}]},
+		{"name":"Nothing","type":"*main.Server","kind":22,"value":"0","children":[{"kind":25}]},
+		{"name":"Next","type":"*main.Server","kind":22,"value":"824634","children":[{"type":"main.Server","kind":25,"len":6}]}
+	]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	got := delveValue(v)
+	want := map[string]any{
+		"Timeout": time.Second,
+		"Ratio":   0.5,
+		"Big":     []any{int64(1), int64(2), "... (198 more)"},
+		"Tags":    map[string]any{"a": int64(1)},
+		"Nothing": nil,
+		"Next":    "main.Server (not read, use a larger -depth or explore its expression)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/emicklei/structexplorer/cmd/structexplorer
+
+go 1.22
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/emicklei/structexplorer v0.10.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// loader decodes the content of a file into generic values such as map[string]any and []any.
+type loader func(data []byte) (any, error)
+
+var loaders = map[string]loader{
+	".json":  loadJSON,
+	".jsonl": loadJSONLines,
+	".yaml":  loadYAML,
+	".yml":   loadYAML,
+	".toml":  loadTOML,
+	".gob":   loadGob,
+	".csv":   loadCSV,
+}
+
+// loadFile reads the file and decodes it using the loader for its extension.
+func loadFile(name string) (any, error) {
+	load, ok := loaders[strings.ToLower(filepath.Ext(name))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported file %q, use one of .json .jsonl .yaml .yml .toml .gob .csv", name)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	v, err := load(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load %q: %w", name, err)
+	}
+	return v, nil
+}
+
+func loadJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return jsonNumbers(v), nil
+}
+
+// loadJSONLines returns the values of all lines.
+func loadJSONLines(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	list := []any{}
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", len(list)+1, err)
+		}
+		list = append(list, jsonNumbers(v))
+	}
+}
+
+// jsonNumbers replaces each json.Number by an int64 or, if it has a fraction or is too large, a float64.
+func jsonNumbers(v any) any {
+	switch tv := v.(type) {
+	case json.Number:
+		if i, err := tv.Int64(); err == nil {
+			return i
+		}
+		f, _ := tv.Float64()
+		return f
+	case map[string]any:
+		for k, each := range tv {
+			tv[k] = jsonNumbers(each)
+		}
+	case []any:
+		for i, each := range tv {
+			tv[i] = jsonNumbers(each)
+		}
+	}
+	return v
+}
+
+// loadYAML returns the value of the document or, if the file has more than one, the values of all documents.
+func loadYAML(data []byte) (any, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	list := []any{}
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	if len(list) == 1 {
+		return list[0], nil
+	}
+	return list, nil
+}
+
+func loadTOML(data []byte) (any, error) {
+	v := map[string]any{}
+	if _, err := toml.Decode(string(data), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func init() {
+	// such that nested generic values can be decoded
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// loadGob decodes a value that was encoded as a map[string]any, a []any or an interface value.
+// The types of values inside interfaces must be registered, which is done for basic and these generic types;
+// a file with an encoded struct cannot be loaded without its Go type.
+func loadGob(data []byte) (any, error) {
+	for _, ptr := range []any{new(map[string]any), new([]any), new(any)} {
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(ptr); err == nil {
+			return reflect.ValueOf(ptr).Elem().Interface(), nil
+		}
+	}
+	return nil, errors.New("gob must contain a map[string]any, []any or an interface value of a registered type")
+}
+
+// csvHeader is set by a flag; if true then the first record has the names of the columns.
+var csvHeader = true
+
+// loadCSV returns a list with a map per record, keyed by the column names of the header,
+// or a list with a list of strings per record.
+func loadCSV(data []byte) (any, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	list := []any{}
+	if !csvHeader {
+		for _, each := range records {
+			list = append(list, each)
+		}
+		return list, nil
+	}
+	if len(records) == 0 {
+		return list, nil
+	}
+	header := records[0]
+	for _, each := range records[1:] {
+		row := map[string]any{}
+		for i, value := range each {
+			column := fmt.Sprintf("column%d", i+1)
+			if i < len(header) && header[i] != "" {
+				column = header[i]
+			}
+			row[column] = value
+		}
+		list = append(list, row)
+	}
+	return list, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emicklei/structexplorer"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	name = filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestLoadFile(t *testing.T) {
+	var gobData bytes.Buffer
+	if err := gob.NewEncoder(&gobData).Encode(map[string]any{"name": "a", "ports": []any{80}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, each := range []struct {
+		name string
+		data string
+		want any
+	}{
+		{"a.json", `{"name":"a","ports":[80,8.5],"id":12345678901234567}`,
+			map[string]any{"name": "a", "ports": []any{int64(80), 8.5}, "id": int64(12345678901234567)}},
+		{"a.jsonl", "{\"n\":1}\n{\"n\":2}\n", []any{map[string]any{"n": int64(1)}, map[string]any{"n": int64(2)}}},
+		{"a.yaml", "name: a\nports:\n  - 80\n", map[string]any{"name": "a", "ports": []any{80}}},
+		{"a.yml", "name: a\n---\nname: b\n", []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}}},
+		{"a.toml", "name = \"a\"\n[server]\nport = 80\n", map[string]any{"name": "a", "server": map[string]any{"port": int64(80)}}},
+		{"a.gob", gobData.String(), map[string]any{"name": "a", "ports": []any{80}}},
+		{"a.csv", "name,port\na,80\nb\n", []any{map[string]any{"name": "a", "port": "80"}, map[string]any{"name": "b"}}},
+	} {
+		t.Run(each.name, func(t *testing.T) {
+			got, err := loadFile(writeFile(t, each.name, []byte(each.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, each.want) {
+				t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, each.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	for _, each := range []string{"a.txt", "a.json", "a.gob"} {
+		if _, err := loadFile(writeFile(t, each, []byte("{"))); err == nil {
+			t.Errorf("error expected for %s", each)
+		}
+	}
+}
+
+func TestLoadCSVWithoutHeader(t *testing.T) {
+	csvHeader = false
+	defer func() { csvHeader = true }()
+	got, _ := loadCSV([]byte("a,80\n"))
+	if want := []any{[]string{"a", "80"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestDataFileChanged(t *testing.T) {
+	name := writeFile(t, "a.json", []byte(`{"n":1}`))
+	f := &dataFile{name: name, label: "a.json"}
+	s := structexplorer.NewService()
+	if err := f.load(s); err != nil {
+		t.Fatal(err)
+	}
+	if f.changed() {
+		t.Error("unchanged file expected")
+	}
+	later := time.Now().Add(time.Second)
+	os.WriteFile(name, []byte(`{"n":12}`), 0644)
+	os.Chtimes(name, later, later)
+	if !f.changed() {
+		t.Error("changed file expected")
+	}
+	// invalid content is reported once
+	os.WriteFile(name, []byte(`5`), 0644)
+	if err := f.load(s); err == nil {
+		t.Error("error expected for a number")
+	}
+	if f.changed() {
+		t.Error("unchanged file expected after failed load")
+	}
+}
+
+func TestLabelOf(t *testing.T) {
+	names := []string{"a/config.json", "b/config.json", "c/data.csv"}
+	if got, want := labelOf("a/config.json", names), "a/config.json"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := labelOf("c/data.csv", names), "data.csv"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
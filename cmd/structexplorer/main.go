@@ -0,0 +1,86 @@
+// Command structexplorer explores the values of JSON, YAML, TOML, gob and CSV files in the Browser.
+//
+// Usage:
+//
+//	structexplorer [flags] file...
+//	structexplorer viewer [-port n] agent-url...
+//	structexplorer delve [flags] [expression...]
+//
+// Each file is explored with its name as label; with -watch a file is loaded again when it changes.
+// The viewer explores the values of one or more programs that serve them using Service.StartAgent.
+// The delve mode explores values of a paused process or core file, read from a headless delve server.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/emicklei/structexplorer"
+)
+
+var (
+	oPort     = flag.Int("port", 5656, "HTTP port of the explorer")
+	oWatch    = flag.Bool("watch", false, "load a file again when it changes")
+	oInterval = flag.Duration("interval", time.Second, "interval at which changes of files are checked")
+	oHeader   = flag.Bool("header", true, "the first record of a CSV file has the names of the columns")
+	oAgent    = flag.Bool("agent", false, "serve the values using the agent protocol instead of the page")
+	oEdit     = flag.Bool("edit", false, "allow the agent to change the loaded values")
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "viewer" {
+		runViewer(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "delve" {
+		runDelve(os.Args[2:])
+		return
+	}
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: structexplorer [flags] file...")
+		fmt.Fprintln(os.Stderr, "       structexplorer viewer [-port n] agent-url...")
+		fmt.Fprintln(os.Stderr, "       structexplorer delve [flags] [expression...]")
+		fmt.Fprintln(os.Stderr, "files: .json .jsonl .yaml .yml .toml .gob .csv")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	csvHeader = *oHeader
+
+	s := structexplorer.NewService()
+	files := []*dataFile{}
+	for _, each := range flag.Args() {
+		f := &dataFile{name: each, label: labelOf(each, flag.Args())}
+		if err := f.load(s); err != nil {
+			slog.Error("[structexplorer] cannot load file", "file", each, "err", err)
+			os.Exit(1)
+		}
+		files = append(files, f)
+	}
+	if *oWatch {
+		go watch(s, files, *oInterval)
+	}
+	if *oAgent {
+		s.StartAgent(structexplorer.Options{HTTPPort: *oPort, AllowEdit: *oEdit})
+		return
+	}
+	s.Start(structexplorer.Options{HTTPPort: *oPort})
+}
+
+// labelOf returns the base name of the file, or the name as given if another file has the same base name.
+func labelOf(name string, names []string) string {
+	base := filepath.Base(name)
+	for _, each := range names {
+		if each != name && filepath.Base(each) == base {
+			return name
+		}
+	}
+	return base
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/emicklei/structexplorer"
+)
+
+// runViewer serves the page that explores the values of the agents given as arguments.
+func runViewer(args []string) {
+	fs := flag.NewFlagSet("viewer", flag.ExitOnError)
+	port := fs.Int("port", 5656, "HTTP port of the viewer")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: structexplorer viewer [-port n] agent-url...")
+		fmt.Fprintln(os.Stderr, "e.g.   structexplorer viewer http://localhost:5657/ http://orders:5657/")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	slog.Info(fmt.Sprintf("starting go struct explorer viewer at http://localhost:%d/ on %v", *port, fs.Args()))
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", *port), structexplorer.NewViewer(fs.Args()...)); err != nil {
+		slog.Error("[structexplorer] failed to start viewer", "err", err)
+		os.Exit(1)
+	}
+}
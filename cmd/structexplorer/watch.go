@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/emicklei/structexplorer"
+)
+
+// dataFile is a file of which the value is explored with a label.
+type dataFile struct {
+	name, label string
+	// of the last load, to detect changes
+	modTime time.Time
+	size    int64
+}
+
+// load explores the value of the file, replacing the value of a previous load.
+func (f *dataFile) load(s structexplorer.Service) error {
+	info, err := os.Stat(f.name)
+	if err != nil {
+		return err
+	}
+	// also if the content is invalid, such that it is loaded again after the next change only
+	f.modTime, f.size = info.ModTime(), info.Size()
+	v, err := loadFile(f.name)
+	if err != nil {
+		return err
+	}
+	if k := reflect.ValueOf(v).Kind(); k != reflect.Map && k != reflect.Slice {
+		return fmt.Errorf("cannot explore value of type %T in %q", v, f.name)
+	}
+	s.Explore(f.label, v)
+	return nil
+}
+
+// changed returns true if the file was modified since the last load.
+func (f *dataFile) changed() bool {
+	info, err := os.Stat(f.name)
+	if err != nil {
+		// e.g. replaced by an editor, try again later
+		return false
+	}
+	return !info.ModTime().Equal(f.modTime) || info.Size() != f.size
+}
+
+// watch loads each file that changed, checking all files every interval.
+func watch(s structexplorer.Service, files []*dataFile, interval time.Duration) {
+	for range time.Tick(interval) {
+		for _, each := range files {
+			if !each.changed() {
+				continue
+			}
+			if err := each.load(s); err != nil {
+				slog.Warn("[structexplorer] cannot reload file", "file", each.name, "err", err)
+				continue
+			}
+			slog.Info("[structexplorer] reloaded file", "file", each.name)
+		}
+	}
+}
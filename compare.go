@@ -0,0 +1,355 @@
+package structexplorer
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompareOption is a type for the options that can be passed to Compare.
+type CompareOption struct {
+	align bool
+}
+
+// AlignElements compares the elements of slices and arrays by finding the longest common subsequence
+// such that an inserted or removed element is reported once instead of as a change of all elements after it.
+func AlignElements() CompareOption {
+	return CompareOption{align: true}
+}
+
+// comparison is a pair of path expressions of which the values are compared each time the page is built.
+type comparison struct {
+	left, right string
+	align       bool
+}
+
+const (
+	diffEqual   = "equal"
+	diffChanged = "changed"
+	diffMissing = "missing" // only in the left value
+	diffExtra   = "extra"   // only in the right value
+)
+
+// maxCompareRows is the maximum number of rows of a comparison.
+var maxCompareRows = 1000
+
+// maxCompareDepth is the maximum number of fields between a compared value and a compared field.
+var maxCompareDepth = 10
+
+// maxAlignCells is the maximum product of the lengths of slices that are aligned; longer ones are compared by index.
+// Each cell needs a comparison of two elements, e.g. two slices of 200 elements.
+var maxAlignCells = 40_000
+
+// Compare adds a comparison of the values at two labels or path expressions, e.g. "expected" and "actual".
+// It is shown as a tree of the fields of both values, listing differing, missing and extra fields;
+// fields with equal values are not expanded.
+func (s *service) Compare(labelA, labelB string, options ...CompareOption) Service {
+	defer s.protect()()
+	s.compare(labelA, labelB, options...)
+	return s
+}
+
+// pre: protected
+func (s *service) compare(left, right string, options ...CompareOption) {
+	c := &comparison{left: left, right: right}
+	for _, each := range options {
+		c.align = c.align || each.align
+	}
+	for i, each := range s.comparisons {
+		if each.left == left && each.right == right {
+			s.comparisons[i] = c
+			return
+		}
+	}
+	s.comparisons = append(s.comparisons, c)
+}
+
+// pre: protected
+func (s *service) uncompare(left, right string) {
+	for i, each := range s.comparisons {
+		if each.left == left && each.right == right {
+			s.comparisons = append(s.comparisons[:i], s.comparisons[i+1:]...)
+			return
+		}
+	}
+}
+
+// compareSnapshot is a comparison with its values, resolved while holding the lock.
+type compareSnapshot struct {
+	comparison  *comparison
+	left, right any
+	err         error
+}
+
+// compareSnapshots resolves the values of all comparisons in order of registration.
+// pre: protected
+func (s *service) compareSnapshots() (list []compareSnapshot) {
+	for _, each := range s.comparisons {
+		snapshot := compareSnapshot{comparison: each}
+		snapshot.left, snapshot.err = s.explorer.stableValueForPath(each.left)
+		if snapshot.err == nil {
+			snapshot.right, snapshot.err = s.explorer.stableValueForPath(each.right)
+		}
+		list = append(list, snapshot)
+	}
+	return
+}
+
+// compareEntries compares the values of the snapshots. It is called without holding the lock
+// such that calls to Explore are not blocked by comparing large values.
+func compareEntries(snapshots []compareSnapshot) (list []compareEntry) {
+	for _, each := range snapshots {
+		c := each.comparison
+		entry := compareEntry{Left: c.left, Right: c.right, Align: c.align}
+		if each.err != nil {
+			entry.Error = each.err.Error()
+		} else {
+			d := &differ{align: c.align, seen: map[[2]uintptr]bool{}}
+			d.diff(0, c.left+" ⇆ "+c.right, each.left, each.right, true, true)
+			entry.Rows = d.rows
+			entry.Differences = d.differences
+		}
+		list = append(list, entry)
+	}
+	return
+}
+
+// stableValueForPath returns the value at the path expression; if its root has a locker then a copy is
+// returned that was made while holding it such that the value can be read after releasing it.
+// pre: protected
+func (e *explorer) stableValueForPath(expr string) (any, error) {
+	parsed, err := parsePath(expr, e.rootLabels())
+	if err != nil {
+		return nil, err
+	}
+	root, _, _, _ := e.rootAccessWithLabel(parsed.root)
+	defer root.lock()()
+	v, err := resolveAccessPath(root.object, parsed.keys)
+	if err != nil {
+		return nil, err
+	}
+	if root.locker != nil {
+		return deepCopy(v), nil
+	}
+	return v, nil
+}
+
+// differ walks two values using the fields of the explorer and collects the rows of a merged tree.
+type differ struct {
+	align       bool
+	rows        []compareRow
+	differences int                 // number of rows that are not equal
+	seen        map[[2]uintptr]bool // pairs of pointers that are compared, to stop cycles
+}
+
+func (d *differ) add(level int, label, left, right, kind string) bool {
+	if kind != diffEqual {
+		d.differences++
+	}
+	if len(d.rows) == maxCompareRows {
+		d.rows = append(d.rows, compareRow{Label: "...", Kind: diffChanged})
+	}
+	if len(d.rows) > maxCompareRows {
+		return false
+	}
+	d.rows = append(d.rows, compareRow{
+		Padding: template.HTML(strings.Repeat("&nbsp;&nbsp;", level)),
+		Label:   label,
+		Left:    left,
+		Right:   right,
+		Kind:    kind,
+	})
+	return true
+}
+
+// diff adds a row for the pair of values and, if both can be explored and are different, the rows of their fields.
+func (d *differ) diff(level int, label string, left, right any, hasLeft, hasRight bool) {
+	if !hasRight {
+		d.add(level, label, safePrintString(left), "", diffMissing)
+		return
+	}
+	if !hasLeft {
+		d.add(level, label, "", safePrintString(right), diffExtra)
+		return
+	}
+	leftString, rightString := safePrintString(left), safePrintString(right)
+	sameType := reflect.TypeOf(left) == reflect.TypeOf(right)
+	if sameType && reflect.DeepEqual(left, right) {
+		d.add(level, label, leftString, rightString, diffEqual)
+		return
+	}
+	if !sameType || left == nil || !canExplore(left) || !canExplore(right) {
+		kind := diffChanged
+		if sameType && leftString == rightString && !canExplore(left) {
+			// e.g. functions, which are never deeply equal
+			kind = diffEqual
+		}
+		d.add(level, label, leftString, rightString, kind)
+		return
+	}
+	if !d.add(level, label, leftString, rightString, diffChanged) || level == maxCompareDepth {
+		return
+	}
+	if lp, rp := pointerOf(left), pointerOf(right); lp != 0 && rp != 0 {
+		pair := [2]uintptr{lp, rp}
+		if d.seen[pair] {
+			return
+		}
+		d.seen[pair] = true
+	}
+	switch kindOf(left) {
+	case reflect.Struct:
+		for _, each := range newFields(left) {
+			l := fieldAccess{owner: left, key: each.key}.value()
+			r := fieldAccess{owner: right, key: each.key}.value()
+			d.diff(level+1, each.key, l, r, true, true)
+		}
+	case reflect.Map:
+		d.diffMaps(level+1, left, right)
+	case reflect.Slice, reflect.Array:
+		d.diffElements(level+1, left, right)
+	}
+}
+
+// pointerOf returns the address of a pointer value, 0 otherwise.
+func pointerOf(v any) uintptr {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		return rv.Pointer()
+	}
+	return 0
+}
+
+// diffMaps compares the values of the keys of both maps, in natural order of the keys.
+func (d *differ) diffMaps(level int, left, right any) {
+	type mapKey struct {
+		label, key      string
+		inLeft, inRight bool
+	}
+	keys := map[string]*mapKey{}
+	for i, each := range []any{left, right} {
+		rv := reflect.Indirect(reflect.ValueOf(each))
+		for _, key := range rv.MapKeys() {
+			encoded := reflectMapKeyToString(key)
+			k, ok := keys[encoded]
+			if !ok {
+				k = &mapKey{label: printString(key.Interface()), key: encoded}
+				keys[encoded] = k
+			}
+			if i == 0 {
+				k.inLeft = true
+			} else {
+				k.inRight = true
+			}
+		}
+	}
+	list := make([]*mapKey, 0, len(keys))
+	for _, each := range keys {
+		list = append(list, each)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].label == list[j].label {
+			return list[i].key < list[j].key
+		}
+		return naturalLess(list[i].label, list[j].label)
+	})
+	for _, each := range list {
+		var l, r any
+		if each.inLeft {
+			l = fieldAccess{owner: left, key: each.key}.value()
+		}
+		if each.inRight {
+			r = fieldAccess{owner: right, key: each.key}.value()
+		}
+		d.diff(level, each.label, l, r, each.inLeft, each.inRight)
+	}
+}
+
+// diffElements compares the elements of two slices or arrays by index or, if aligning, by their longest common subsequence.
+func (d *differ) diffElements(level int, left, right any) {
+	lv, rv := reflect.Indirect(reflect.ValueOf(left)), reflect.Indirect(reflect.ValueOf(right))
+	element := func(v any, i int) any {
+		return fieldAccess{owner: v, key: strconv.Itoa(i)}.value()
+	}
+	if !d.align || lv.Len()*rv.Len() > maxAlignCells {
+		for i := 0; i < max(lv.Len(), rv.Len()); i++ {
+			var l, r any
+			if i < lv.Len() {
+				l = element(left, i)
+			}
+			if i < rv.Len() {
+				r = element(right, i)
+			}
+			d.diff(level, strconv.Itoa(i), l, r, i < lv.Len(), i < rv.Len())
+		}
+		return
+	}
+	ls, rs := make([]any, lv.Len()), make([]any, rv.Len())
+	for i := range ls {
+		ls[i] = element(left, i)
+	}
+	for j := range rs {
+		rs[j] = element(right, j)
+	}
+	i, j := 0, 0
+	for _, match := range alignEqual(ls, rs) {
+		d.diffGap(level, ls, rs, i, match[0], j, match[1])
+		d.diff(level, indexPairLabel(match[0], match[1]), ls[match[0]], rs[match[1]], true, true)
+		i, j = match[0]+1, match[1]+1
+	}
+	d.diffGap(level, ls, rs, i, len(ls), j, len(rs))
+}
+
+// diffGap compares the elements between two aligned pairs; elements are paired up as changes
+// and the remaining ones are missing or extra.
+func (d *differ) diffGap(level int, ls, rs []any, i, toI, j, toJ int) {
+	for ; i < toI && j < toJ; i, j = i+1, j+1 {
+		d.diff(level, indexPairLabel(i, j), ls[i], rs[j], true, true)
+	}
+	for ; i < toI; i++ {
+		d.diff(level, strconv.Itoa(i), ls[i], nil, true, false)
+	}
+	for ; j < toJ; j++ {
+		d.diff(level, strconv.Itoa(j), nil, rs[j], false, true)
+	}
+}
+
+func indexPairLabel(i, j int) string {
+	if i == j {
+		return strconv.Itoa(i)
+	}
+	return fmt.Sprintf("%d→%d", i, j)
+}
+
+// alignEqual returns the index pairs of the longest common subsequence of deeply equal elements.
+func alignEqual(ls, rs []any) (pairs [][2]int) {
+	// lengths[i][j] is the length of the LCS of ls[i:] and rs[j:]
+	lengths := make([][]int, len(ls)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(rs)+1)
+	}
+	for i := len(ls) - 1; i >= 0; i-- {
+		for j := len(rs) - 1; j >= 0; j-- {
+			if reflect.DeepEqual(ls[i], rs[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(ls) && j < len(rs); {
+		switch {
+		case reflect.DeepEqual(ls[i], rs[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return
+}
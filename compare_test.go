@@ -0,0 +1,143 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type comparedReplica struct {
+	Name    string
+	Version int
+	Peers   []string
+	Config  map[string]string
+	Leader  *comparedReplica
+}
+
+func rowsOf(entry compareEntry) (list []string) {
+	for _, each := range entry.Rows {
+		if each.Kind != diffEqual {
+			list = append(list, strings.ReplaceAll(string(each.Padding), "&nbsp;", " ")+each.Label+" "+each.Kind+" "+each.Left+" "+each.Right)
+		}
+	}
+	return
+}
+
+func TestCompare(t *testing.T) {
+	a := &comparedReplica{Name: "a", Version: 1, Peers: []string{"x", "y", "z"}, Config: map[string]string{"mode": "fast", "old": "1"}}
+	b := &comparedReplica{Name: "a", Version: 2, Peers: []string{"w", "x", "y", "z"}, Config: map[string]string{"mode": "slow", "new": "2"}}
+	s := NewService("a", a, "b", b).(*service)
+	s.Compare("a", "b")
+
+	list := compareEntries(s.compareSnapshots())
+	if got, want := len(list), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	got := strings.Join(rowsOf(list[0]), "\n")
+	if !strings.HasPrefix(got, "a ⇆ b changed") || !strings.Contains(got, "  Version changed 1 2") {
+		t.Errorf("got\n%s", got)
+	}
+	for _, each := range []string{
+		`    0 changed "x" "w"`,
+		`    3 extra  "z"`,
+		`    "mode" changed "fast" "slow"`,
+		`    "new" extra  "2"`,
+		`    "old" missing "1" `,
+	} {
+		if !strings.Contains(got, each) {
+			t.Errorf("missing %q in\n%s", each, got)
+		}
+	}
+
+	// aligned, only the inserted peer is different
+	s.Compare("a", "b", AlignElements())
+	got = strings.Join(rowsOf(compareEntries(s.compareSnapshots())[0]), "\n")
+	if !strings.Contains(got, `    0 extra  "w"`) || strings.Contains(got, `"x" "w"`) {
+		t.Errorf("got\n%s", got)
+	}
+
+	s.uncompare("a", "b")
+	if got, want := len(compareEntries(s.compareSnapshots())), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestCompareCycleAndErrors(t *testing.T) {
+	a := &comparedReplica{Name: "a"}
+	a.Leader = a
+	b := &comparedReplica{Name: "b"}
+	b.Leader = b
+	s := NewService("a", a, "b", b).(*service)
+	s.Compare("a", "b")
+	s.Compare("a", "c.Name")
+	list := compareEntries(s.compareSnapshots())
+	// root, name and leader; the cycle is not followed
+	if got, want := list[0].Differences, 3; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T] %v", got, want, rowsOf(list[0]))
+	}
+	if list[1].Error == "" {
+		t.Error("error expected for unknown root")
+	}
+}
+
+func TestAlignEqual(t *testing.T) {
+	pairs := alignEqual([]any{1, 2, 3, 4}, []any{0, 1, 3, 4, 5})
+	if got, want := len(pairs), 3; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := pairs[1], [2]int{2, 2}; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestServeCompare(t *testing.T) {
+	a := &comparedReplica{Name: "a"}
+	b := &comparedReplica{Name: "b"}
+	s := NewService("a", a, "b", b).(*service)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"action":"compare","row":1,"column":0,"selections":["0,0"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.comparisons[0].left+" "+s.comparisons[0].right, "a b"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `<table class="comparison">`) {
+		t.Error("comparison expected")
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"action":"compare","row":1,"column":0,"selections":["5,5"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusBadRequest; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestCompareAlignLimit(t *testing.T) {
+	short := &comparedReplica{Peers: []string{"x", "y", "z"}}
+	long := &comparedReplica{Peers: make([]string, 300)}
+	for i := range long.Peers {
+		long.Peers[i] = strconv.Itoa(i)
+	}
+	inserted := &comparedReplica{Peers: append([]string{"new"}, long.Peers...)}
+	s := NewService("short", short, "long", long, "inserted", inserted).(*service)
+	s.Compare("short", "short", AlignElements())
+	s.Compare("long", "inserted", AlignElements())
+	list := compareEntries(s.compareSnapshots())
+	if got, want := list[0].Differences, 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// 300 x 301 elements exceed maxAlignCells, so all elements after the inserted one differ by index
+	if got := list[1].Differences; got < 300 {
+		t.Errorf("got [%[1]v:%[1]T] want at least 300", got)
+	}
+}
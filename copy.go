@@ -0,0 +1,97 @@
+package structexplorer
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// deepCopy returns a copy of the value that shares no memory with it, including unexported fields.
+// Pointers to the same value are copied once such that cycles are kept.
+// Channels, functions and unsafe pointers are not copied.
+func deepCopy(v any) any {
+	if v == nil {
+		return nil
+	}
+	c := copier{pointers: map[copiedPointer]reflect.Value{}}
+	return c.copyValue(reflect.ValueOf(v)).Interface()
+}
+
+type copiedPointer struct {
+	address uintptr
+	typ     reflect.Type
+}
+
+type copier struct {
+	pointers map[copiedPointer]reflect.Value
+}
+
+// copyValue returns an addressable copy of src.
+func (c copier) copyValue(src reflect.Value) reflect.Value {
+	dst := reflect.New(src.Type()).Elem()
+	c.copyInto(dst, src)
+	return dst
+}
+
+// copyInto copies src into dst which must be settable.
+func (c copier) copyInto(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := copiedPointer{address: src.Pointer(), typ: src.Type()}
+		if p, ok := c.pointers[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.pointers[key] = p
+		c.copyInto(p.Elem(), src.Elem())
+		dst.Set(p)
+	case reflect.Struct:
+		if !src.CanAddr() {
+			// fields must be addressable to read unexported ones
+			tmp := reflect.New(src.Type()).Elem()
+			tmp.Set(src)
+			src = tmp
+		}
+		for i := range src.NumField() {
+			c.copyInto(accessible(dst.Field(i)), accessible(src.Field(i)))
+		}
+	case reflect.Array:
+		for i := range src.Len() {
+			c.copyInto(dst.Index(i), src.Index(i))
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			c.copyInto(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(c.copyValue(iter.Key()), c.copyValue(iter.Value()))
+		}
+		dst.Set(m)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(c.copyValue(src.Elem()))
+	default:
+		dst.Set(src)
+	}
+}
+
+// accessible returns the addressable value such that it can be read and set, also if it is an unexported field.
+func accessible(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
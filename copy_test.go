@@ -0,0 +1,107 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type copyNode struct {
+	name     string
+	Next     *copyNode
+	Children []*copyNode
+	Attrs    map[string]any
+	Fixed    [2]int
+}
+
+func TestDeepCopy(t *testing.T) {
+	root := &copyNode{name: "root", Attrs: map[string]any{"n": 1, "list": []int{1}}, Fixed: [2]int{1, 2}}
+	child := &copyNode{name: "child", Next: root}
+	root.Next = child
+	root.Children = []*copyNode{child}
+
+	c := deepCopy(root).(*copyNode)
+	root.name = "changed"
+	child.name = "changed"
+	root.Attrs["n"] = 2
+	root.Attrs["list"].([]int)[0] = 2
+	root.Fixed[0] = 0
+
+	if got, want := c.name, "root"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := c.Next.name, "child"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := c.Attrs["n"], 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := c.Attrs["list"].([]int)[0], 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := c.Fixed[0], 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// cycle and shared pointer are kept
+	if c.Next.Next != c || c.Children[0] != c.Next {
+		t.Error("expected shared pointers in copy")
+	}
+}
+
+type lockedCounter struct {
+	mu    sync.Mutex
+	Count int
+	Names map[string]int
+}
+
+func (c *lockedCounter) ExploreLocker() sync.Locker { return &c.mu }
+
+func (c *lockedCounter) inc() {
+	c.mu.Lock()
+	c.Count++
+	c.Names["a"] = c.Count
+	c.mu.Unlock()
+}
+
+// run with -race to detect unlocked reads
+func TestExploreWithLocker(t *testing.T) {
+	counter := &lockedCounter{Names: map[string]int{}}
+	other := &lockedCounter{Names: map[string]int{}}
+	s := NewService().(*service)
+	s.Explore("counter", counter)
+	s.Explore("other", other, WithLocker(&other.mu), Row(1))
+	s.ExplorePath("counter.Names")
+
+	done := make(chan bool)
+	go func() {
+		for range 100 {
+			counter.inc()
+			other.inc()
+		}
+		done <- true
+	}()
+	for range 10 {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		s.ServeHTTP(rec, req)
+	}
+	<-done
+	if got, want := s.explorer.objectAt(0, 1).locker, sync.Locker(&counter.mu); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestExploreCopy(t *testing.T) {
+	counter := &lockedCounter{Count: 1, Names: map[string]int{}}
+	s := NewService().(*service)
+	s.Explore("counter", counter, CopyOnExplore())
+	counter.inc()
+	oa := s.explorer.objectAt(0, 0)
+	if got, want := oa.Value().(*lockedCounter).Count, 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if oa.locker != nil {
+		t.Error("copy must not have a locker")
+	}
+}
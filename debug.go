@@ -0,0 +1,102 @@
+package structexplorer
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// debugPath is the path of the explorer registered by RegisterDebug, next to /debug/vars and /debug/pprof/.
+const debugPath = "/debug/explore/"
+
+// expvarLabel is the label of the published variables that cannot be explored by themselves, such as an expvar.Int.
+const expvarLabel = "expvar"
+
+// debugEndpoints are the standard debug handlers that are linked from the page if registered on the ServeMux.
+var debugEndpoints = []debugLink{
+	{Name: "vars", URL: "/debug/vars"},
+	{Name: "pprof", URL: "/debug/pprof/"},
+	{Name: "goroutines", URL: "/debug/pprof/goroutine?debug=1"},
+	{Name: "heap", URL: "/debug/pprof/heap?debug=1"},
+	{Name: "cpu profile (30s)", URL: "/debug/pprof/profile?seconds=30"},
+	{Name: "trace (1s)", URL: "/debug/pprof/trace?seconds=1"},
+}
+
+// RegisterDebug serves the explorer on the ServeMux at /debug/explore/, next to the handlers of expvar and net/http/pprof.
+// If mux is nil then http.DefaultServeMux is used.
+// All published expvar variables are explored with their name as label and are read again each time the page is requested;
+// variables that cannot be explored by themselves, such as numbers, are the fields of the value labeled "expvar".
+// The page links to the /debug/vars and /debug/pprof/ endpoints that are registered on the ServeMux.
+func RegisterDebug(mux *http.ServeMux, labelValuePairs ...any) Service {
+	if mux == nil {
+		mux = http.DefaultServeMux
+	}
+	s := NewService(labelValuePairs...).(*service)
+	s.explorer.options.HTTPBasePath = debugPath
+	s.debugMux = mux
+	s.exploreExpvars()
+	mux.Handle(debugPath, s)
+	return s
+}
+
+// exploreExpvars explores the current values of all published expvar variables, replacing those of a previous call.
+func (s *service) exploreExpvars() {
+	// collect first; expvar.Do holds its lock while calling
+	vars := map[string]any{}
+	expvar.Do(func(kv expvar.KeyValue) {
+		vars[kv.Key] = expvarValue(kv.Value)
+	})
+	names := make([]string, 0, len(vars))
+	for each := range vars {
+		names = append(names, each)
+	}
+	sort.Strings(names)
+	scalars := map[string]any{}
+	for _, each := range names {
+		v := vars[each]
+		if v == nil || !canExplore(v) {
+			scalars[each] = v
+			continue
+		}
+		s.Explore(each, v)
+	}
+	if len(scalars) > 0 {
+		s.Explore(expvarLabel, scalars)
+	}
+}
+
+// expvarValue returns the value of a variable; a Func returns its value, e.g. the runtime.MemStats of "memstats",
+// other variables that are not of the expvar package are decoded from their JSON.
+func expvarValue(v expvar.Var) any {
+	switch ev := v.(type) {
+	case expvar.Func:
+		return ev.Value()
+	case *expvar.Int:
+		return ev.Value()
+	case *expvar.Float:
+		return ev.Value()
+	case *expvar.String:
+		return ev.Value()
+	}
+	var generic any
+	if err := json.Unmarshal([]byte(v.String()), &generic); err != nil {
+		return v.String()
+	}
+	return generic
+}
+
+// debugLinks returns the debug endpoints that are registered on the ServeMux of RegisterDebug, if any.
+func (s *service) debugLinks() (list []debugLink) {
+	if s.debugMux == nil {
+		return
+	}
+	for _, each := range debugEndpoints {
+		u, _ := url.Parse(each.URL)
+		if _, pattern := s.debugMux.Handler(&http.Request{Method: http.MethodGet, URL: u, Host: "localhost"}); pattern != "" {
+			list = append(list, each)
+		}
+	}
+	return
+}
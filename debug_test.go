@@ -0,0 +1,55 @@
+package structexplorer
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegisterDebug(t *testing.T) {
+	requests := expvar.NewInt("test.debug.requests")
+	requests.Set(3)
+	expvar.Publish("test.debug.config", expvar.Func(func() any {
+		return map[string]any{"mode": "debug"}
+	}))
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	s := RegisterDebug(mux).(*service)
+
+	keys := s.explorer.rootKeys()
+	if !slices.Contains(keys, "test.debug.config") || !slices.Contains(keys, expvarLabel) {
+		t.Fatalf("missing roots %v", keys)
+	}
+	if got, want := expvarField(s, "test.debug.requests"), any(int64(3)); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// read again on each page
+	requests.Set(4)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/debug/explore/", nil)
+	mux.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := expvarField(s, "test.debug.requests"), any(int64(4)); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// only registered endpoints are linked
+	page := rec.Body.String()
+	if !strings.Contains(page, `href="/debug/vars"`) {
+		t.Error("link to vars expected")
+	}
+	if strings.Contains(page, `/debug/pprof/`) {
+		t.Error("no link to pprof expected")
+	}
+}
+
+func expvarField(s *service, name string) any {
+	defer s.protect()()
+	access, _, _, _ := s.explorer.rootAccessWithLabel(expvarLabel)
+	return access.Value().(map[string]any)[name]
+}
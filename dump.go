@@ -0,0 +1,219 @@
+package structexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the output format of Service.DumpTo.
+type Format int
+
+const (
+	// FormatHTML is the page with the explored values, without the controls to explore further.
+	FormatHTML Format = iota
+	// FormatOfflineHTML is the page with the values reachable from the explored values, see DumpOptions.
+	FormatOfflineHTML
+	// FormatJSON lists the explored values with the label, path, type and value of each field.
+	FormatJSON
+	// FormatYAML has the same structure as FormatJSON.
+	FormatYAML
+	// FormatMarkdown has a table with the fields of each explored value.
+	FormatMarkdown
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatHTML:
+		return "html"
+	case FormatOfflineHTML:
+		return "offline html"
+	case FormatJSON:
+		return "json"
+	case FormatYAML:
+		return "yaml"
+	case FormatMarkdown:
+		return "markdown"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// formatOfFile returns the format that matches the extension of the filename; FormatHTML is the default.
+func formatOfFile(filename string) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".md":
+		return FormatMarkdown
+	}
+	return FormatHTML
+}
+
+type (
+	// dumpDocument is the structured form of a page, see FormatJSON.
+	dumpDocument struct {
+		Cells []dumpCell `json:"cells" yaml:"cells"`
+	}
+	dumpCell struct {
+		Row    int         `json:"row" yaml:"row"`
+		Column int         `json:"column" yaml:"column"`
+		Label  string      `json:"label" yaml:"label"`
+		Path   string      `json:"path,omitempty" yaml:"path,omitempty"` // empty if not explored from a root
+		Type   string      `json:"type" yaml:"type"`
+		Length int         `json:"length,omitempty" yaml:"length,omitempty"`
+		Fields []dumpField `json:"fields" yaml:"fields"`
+	}
+	dumpField struct {
+		Label string `json:"label" yaml:"label"`
+		Path  string `json:"path" yaml:"path"`
+		Type  string `json:"type" yaml:"type"`
+		Value string `json:"value" yaml:"value"`
+	}
+)
+
+// DumpTo writes the current state of the explorer in a format. DumpOptions only apply to FormatOfflineHTML.
+func (s *service) DumpTo(w io.Writer, format Format, opts ...DumpOptions) error {
+	defer s.protect()()
+	return s.dumpTo(w, format, opts...)
+}
+
+// dumpFile creates the file and writes the current state of the explorer in a format.
+func (s *service) dumpFile(filename string, format Format, opts ...DumpOptions) error {
+	defer s.protect()()
+	out, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := s.dumpTo(out, format, opts...); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// pre: protected
+func (s *service) dumpTo(w io.Writer, format Format, opts ...DumpOptions) error {
+	switch format {
+	case FormatOfflineHTML:
+		options := DumpOptions{}
+		if len(opts) > 0 {
+			options = opts[0]
+		}
+		return writeOfflineHTML(w, s.explorer.offlineDump(options, time.Now()))
+	case FormatHTML, FormatJSON, FormatYAML, FormatMarkdown:
+		b := newIndexDataBuilder()
+		b.notLive = true
+		data := s.explorer.buildIndexData(b)
+		switch format {
+		case FormatJSON:
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+			return enc.Encode(newDumpDocument(data))
+		case FormatYAML:
+			return writeYAML(w, newDumpDocument(data))
+		case FormatMarkdown:
+			return writeMarkdown(w, newDumpDocument(data))
+		}
+		return s.indexTemplate.Execute(w, data)
+	}
+	return fmt.Errorf("unknown dump format: %v", format)
+}
+
+func newDumpDocument(data indexData) dumpDocument {
+	doc := dumpDocument{Cells: []dumpCell{}}
+	for _, row := range data.Rows {
+		for _, each := range row.Cells {
+			if each.Type == "" {
+				continue
+			}
+			cell := dumpCell{
+				Row:    each.Row,
+				Column: each.Column,
+				Label:  each.Name,
+				Path:   each.ExplorePath,
+				Type:   each.Type,
+				Length: each.Length,
+				Fields: []dumpField{},
+			}
+			for _, field := range each.Fields {
+				cell.Fields = append(cell.Fields, dumpField{
+					Label: field.Label,
+					Path:  field.Path,
+					Type:  field.Type,
+					Value: field.ValueString,
+				})
+			}
+			doc.Cells = append(doc.Cells, cell)
+		}
+	}
+	return doc
+}
+
+// writeYAML writes the document using the same structure as JSON.
+func writeYAML(w io.Writer, doc dumpDocument) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeMarkdown writes a section with a table of fields for each cell.
+func writeMarkdown(w io.Writer, doc dumpDocument) error {
+	b := new(strings.Builder)
+	for i, cell := range doc.Cells {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "### %s\n\n", markdownText(cell.Label))
+		fmt.Fprintf(b, "%s", markdownCode(cell.Type))
+		if cell.Length > 0 {
+			fmt.Fprintf(b, " (%d)", cell.Length)
+		}
+		if cell.Path != "" {
+			fmt.Fprintf(b, " at %s", markdownCode(cell.Path))
+		}
+		b.WriteString("\n\n")
+		if len(cell.Fields) == 0 {
+			b.WriteString("no fields\n")
+			continue
+		}
+		b.WriteString("| field | type | value |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, field := range cell.Fields {
+			fmt.Fprintf(b, "| %s | %s | %s |\n", markdownText(field.Label), markdownCode(field.Type), markdownText(field.Value))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownText escapes characters that would end a table cell or line or start formatting.
+func markdownText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", "\n", " ", "\r", " ",
+	).Replace(s)
+}
+
+// markdownCode returns the text as inline code; pipes are escaped to stay in a table cell.
+func markdownCode(s string) string {
+	s = strings.NewReplacer("|", `\|`, "\n", " ", "\r", " ").Replace(s)
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
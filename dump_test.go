@@ -0,0 +1,134 @@
+package structexplorer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type dumpedOrder struct {
+	ID    int
+	Notes string
+	Lines []dumpedLine
+}
+
+type dumpedLine struct {
+	SKU string
+}
+
+func newDumpedOrderService() Service {
+	order := &dumpedOrder{ID: 7, Notes: "a|b", Lines: []dumpedLine{{SKU: "x"}}}
+	return NewService("order", order).ExplorePath("order.Lines[0]", Column(1))
+}
+
+func TestDumpToJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := newDumpedOrderService().DumpTo(buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	doc := dumpDocument{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(doc.Cells), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := doc.Cells[0].Fields[2].Path, "order.Lines"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	line := doc.Cells[1]
+	if got, want := line.Path, "order.Lines[0]"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := line.Fields[0], (dumpField{Label: "SKU", Path: "order.Lines[0].SKU", Type: "string", Value: `"x"`}); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestDumpToYAML(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := newDumpedOrderService().DumpTo(buf, FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	doc := dumpDocument{}
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(doc.Cells), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := doc.Cells[0].Fields[1], (dumpField{Label: "Notes", Path: "order.Notes", Type: "string", Value: `"a|b"`}); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := doc.Cells[1].Fields[0], (dumpField{Label: "SKU", Path: "order.Lines[0].SKU", Type: "string", Value: `"x"`}); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestWriteYAMLRoundTrip(t *testing.T) {
+	want := dumpDocument{Cells: []dumpCell{{
+		Label: "key: *anchor",
+		Type:  "map[string]string",
+		Fields: []dumpField{
+			{Label: "multi\nline", Path: "m[\"multi\\nline\"]", Type: "string", Value: "\"first\nsecond\"\n"},
+			{Label: "- yes", Path: "m[\"- yes\"]", Type: "string", Value: "&ref # null"},
+		},
+	}}}
+	buf := new(bytes.Buffer)
+	if err := writeYAML(buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got := dumpDocument{}
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v want %#v from\n%s", got, want, buf.String())
+	}
+}
+
+func TestDumpToMarkdown(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := newDumpedOrderService().DumpTo(buf, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, each := range []string{
+		"### order\n\n`*structexplorer.dumpedOrder` at `order`\n\n| field | type | value |\n|---|---|---|\n| ID | `int` | 7 |\n",
+		`| Notes | ` + "`string`" + ` | "a\|b" |`,
+		"### order.Lines[0]\n\n`structexplorer.dumpedLine` at `order.Lines[0]`",
+	} {
+		if !strings.Contains(buf.String(), each) {
+			t.Errorf("missing %q in\n%s", each, buf.String())
+		}
+	}
+}
+
+func TestDumpErrors(t *testing.T) {
+	s := newDumpedOrderService()
+	if err := s.DumpTo(new(bytes.Buffer), Format(99)); err == nil {
+		t.Error("error expected for unknown format")
+	}
+	if err := s.Dump(filepath.Join(t.TempDir(), "missing", "dump.html")); err == nil {
+		t.Error("error expected for missing directory")
+	}
+}
+
+func TestDumpFormatOfFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "dump.yml")
+	if err := newDumpedOrderService().Dump(name); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(name)
+	if !strings.HasPrefix(string(data), "cells:") {
+		t.Errorf("yaml expected, got %s", data)
+	}
+	if got, want := formatOfFile("dump.MD"), FormatMarkdown; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
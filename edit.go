@@ -0,0 +1,143 @@
+package structexplorer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// setValueAtPath changes the value at the path expression to the value parsed from text.
+// pre: protected
+func (e *explorer) setValueAtPath(expr, text string) error {
+	parsed, err := parsePath(expr, e.rootLabels())
+	if err != nil {
+		return err
+	}
+	if len(parsed.keys) == 0 {
+		return fmt.Errorf("cannot change %q, only its fields", expr)
+	}
+	root, _, _, _ := e.rootAccessWithLabel(parsed.root)
+	defer root.lock()()
+	if err := setValue(root.object, parsed.keys, text); err != nil {
+		return fmt.Errorf("cannot change %q: %w", expr, err)
+	}
+	return nil
+}
+
+// setValue changes the value at the keys, starting from the owner, which must be reached through a pointer, slice or map.
+// The text is parsed for the type of the value, or for the type of its current value if it is an interface.
+func setValue(owner any, keys []string, text string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	rv := reflect.ValueOf(owner)
+	for i, key := range keys {
+		if isIntervalKey(key) || isTypeAssertionKey(key) {
+			// the same value
+			continue
+		}
+		for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+			if rv.IsNil() {
+				return fmt.Errorf("nil value before %q", key)
+			}
+			rv = rv.Elem()
+		}
+		switch rv.Kind() {
+		case reflect.Map:
+			mk := reflectMapKeyFor(key, rv)
+			if !mk.IsValid() || !rv.MapIndex(mk).IsValid() {
+				return fmt.Errorf("no map key %s", key)
+			}
+			if i == len(keys)-1 {
+				nv, err := parseValue(text, rv.Type().Elem(), rv.MapIndex(mk))
+				if err != nil {
+					return err
+				}
+				rv.SetMapIndex(mk, nv)
+				return nil
+			}
+			// not addressable, can only be changed if it is a pointer
+			rv = rv.MapIndex(mk)
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= rv.Len() {
+				return fmt.Errorf("no index %s", key)
+			}
+			rv = rv.Index(index)
+		case reflect.Struct:
+			rv = rv.FieldByName(key)
+			if !rv.IsValid() {
+				return fmt.Errorf("no field %s", key)
+			}
+		default:
+			return fmt.Errorf("no key %s in value of type %s", key, rv.Type())
+		}
+		if rv.CanAddr() {
+			// also for unexported fields, as they are read
+			rv = reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem()
+		}
+	}
+	if !rv.CanSet() {
+		return errors.New("value is not reached through a pointer, slice or map")
+	}
+	nv, err := parseValue(text, rv.Type(), rv)
+	if err != nil {
+		return err
+	}
+	rv.Set(nv)
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseValue returns a new value of the type from the text: a string as is, a number, bool or duration,
+// or JSON for other types. For an interface, the type of the current value is used if not nil.
+func parseValue(text string, typ reflect.Type, current reflect.Value) (reflect.Value, error) {
+	if typ.Kind() == reflect.Interface && current.IsValid() && !current.IsNil() {
+		nv, err := parseValue(text, current.Elem().Type(), reflect.Value{})
+		if err != nil {
+			return nv, err
+		}
+		return nv.Convert(typ), nil
+	}
+	nv := reflect.New(typ).Elem()
+	var err error
+	switch typ.Kind() {
+	case reflect.String:
+		nv.SetString(text)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		nv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if typ == durationType {
+			var d time.Duration
+			d, err = time.ParseDuration(text)
+			i = int64(d)
+		} else {
+			i, err = strconv.ParseInt(text, 10, typ.Bits())
+		}
+		nv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, typ.Bits())
+		nv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, typ.Bits())
+		nv.SetFloat(f)
+	default:
+		err = json.Unmarshal([]byte(text), nv.Addr().Interface())
+	}
+	if err != nil {
+		return nv, fmt.Errorf("invalid %s: %w", typ, err)
+	}
+	return nv, nil
+}
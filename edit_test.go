@@ -0,0 +1,86 @@
+package structexplorer
+
+import (
+	"testing"
+	"time"
+)
+
+type editedServer struct {
+	Name    string
+	port    int
+	Timeout time.Duration
+	Tags    map[string]string
+	Limits  []float64
+	Inner   editedInner
+	Config  map[string]any
+}
+
+type editedInner struct {
+	Enabled bool
+}
+
+func TestSetValue(t *testing.T) {
+	s := &editedServer{
+		Tags:   map[string]string{"env": "dev"},
+		Limits: []float64{1, 2},
+		Config: map[string]any{"retries": int64(3), "hosts": []any{"a"}},
+	}
+	for _, each := range []struct {
+		keys []string
+		text string
+	}{
+		{[]string{"Name"}, "api"},
+		{[]string{"port"}, "8080"},
+		{[]string{"Timeout"}, "2s"},
+		{[]string{"Tags", `"env"`}, "prod"},
+		{[]string{"Limits", "1"}, "2.5"},
+		{[]string{"Inner", "Enabled"}, "true"},
+		{[]string{"Config", `"retries"`}, "5"},
+		{[]string{"Config", `"hosts"`}, `["b","c"]`},
+	} {
+		if err := setValue(s, each.keys, each.text); err != nil {
+			t.Errorf("%v: %v", each.keys, err)
+		}
+	}
+	if got, want := s.Name+s.Tags["env"], "apiprod"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.port, 8080; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.Timeout, 2*time.Second; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.Limits[1], 2.5; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !s.Inner.Enabled {
+		t.Error("enabled expected")
+	}
+	// keeps the type of the current value
+	if got, want := s.Config["retries"], any(int64(5)); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := len(s.Config["hosts"].([]any)), 2; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestSetValueErrors(t *testing.T) {
+	s := &editedServer{Tags: map[string]string{}}
+	for _, each := range []struct {
+		owner any
+		keys  []string
+		text  string
+	}{
+		{s, []string{"port"}, "high"},
+		{s, []string{"Missing"}, "1"},
+		{s, []string{"Tags", `"none"`}, "1"},
+		{s, []string{"Limits", "0"}, "1"},
+		{*s, []string{"Name"}, "api"}, // a copy
+	} {
+		if err := setValue(each.owner, each.keys, each.text); err == nil {
+			t.Errorf("%v: error expected", each.keys)
+		}
+	}
+}
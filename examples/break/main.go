@@ -14,6 +14,9 @@ func main() {
 
 	log.Println("before opening the explorer to see state")
 
+	// show these with the stack frame of main
+	defer structexplorer.Locals(map[string]any{"hello": hello})()
+
 	structexplorer.Break("map", greeting)
 
 	log.Println("after opening the explorer to see state")
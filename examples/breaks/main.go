@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	"github.com/emicklei/structexplorer"
+)
+
+// go run .
+func main() {
+	wg := new(sync.WaitGroup)
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := struct{ Number int }{Number: i}
+			// all paused goroutines are listed on the same page
+			structexplorer.Break("worker", worker)
+			log.Println("resumed worker", i)
+		}()
+	}
+	wg.Wait()
+}
@@ -1,10 +1,14 @@
 package structexplorer
 
+import "sync"
+
 type placementFunc func(e *explorer) (newRow, newColumn int)
 
 // ExploreOption is a type for the options that can be passed to the Explore or Follow function.
 type ExploreOption struct {
 	placement placementFunc
+	locker    sync.Locker
+	copy      bool
 }
 
 // RowColumn places the next object in the specified row and column.
@@ -33,3 +37,56 @@ func Row(row int) ExploreOption {
 		},
 	}
 }
+
+// WithLocker sets the lock that is held while reading the explored value and everything reachable from it.
+// The lock must not be held while calling Explore, ExplorePath or Break.
+func WithLocker(l sync.Locker) ExploreOption {
+	return ExploreOption{locker: l}
+}
+
+// CopyOnExplore explores a deep copy of the value such that it no longer changes.
+// If the value has a locker then the copy is made while holding it.
+// Explore the value again to refresh the copy.
+func CopyOnExplore() ExploreOption {
+	return ExploreOption{copy: true}
+}
+
+// Locker can be implemented by an explored value to provide the lock that protects it from concurrent changes.
+// The explorer holds the lock while reading the value and everything reachable from it.
+// A locker set using WithLocker takes precedence.
+type Locker interface {
+	ExploreLocker() sync.Locker
+}
+
+// placementOf returns the first option with a placement or the default.
+func placementOf(options []ExploreOption, defaultOption ExploreOption) ExploreOption {
+	for _, each := range options {
+		if each.placement != nil {
+			return each
+		}
+	}
+	return defaultOption
+}
+
+// lockerOf returns the locker from the options or the value, nil if there is none.
+func lockerOf(value any, options []ExploreOption) sync.Locker {
+	for _, each := range options {
+		if each.locker != nil {
+			return each.locker
+		}
+	}
+	if l, ok := value.(Locker); ok {
+		return l.ExploreLocker()
+	}
+	return nil
+}
+
+// isCopyOnExplore returns true if one of the options is CopyOnExplore.
+func isCopyOnExplore(options []ExploreOption) bool {
+	for _, each := range options {
+		if each.copy {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -12,15 +15,69 @@ type objectAccess struct {
 	object     any
 	path       []string
 	label      string
+	rootLabel  string // label of the root the path starts from, empty if not from a root
 	typeName   string
 	hideZeros  bool
 	sliceRange interval
+	sortBy     string      // empty, key, value or type
+	filter     string      // substring or /regexp/ that entries must match
+	locker     sync.Locker // of the root, held while reading its values, can be nil
+}
+
+// lock acquires the locker of the root, if any, and returns the function to release it.
+func (o objectAccess) lock() func() {
+	if o.locker == nil {
+		return func() {}
+	}
+	o.locker.Lock()
+	return o.locker.Unlock
 }
 
 func (o objectAccess) Value() any {
 	return valueAtAccessPath(o.object, o.path)
 }
 
+// explorePath returns the path expression that can be passed to ExplorePath, empty if not from a root.
+func (o objectAccess) explorePath() string {
+	if o.rootLabel == "" {
+		return ""
+	}
+	return formatPath(o.object, o.rootLabel, o.path)
+}
+
+// child returns the access to the value of a field, or false if that value cannot be explored.
+// pre: the lock of the root is held, see lock
+func (o objectAccess) child(key string) (objectAccess, bool) {
+	newPath := append(append([]string{}, o.path...), key)
+	oa := objectAccess{
+		object:    o.object,
+		path:      newPath,
+		label:     strings.Join(newPath, "."),
+		rootLabel: o.rootLabel,
+		locker:    o.locker,
+		hideZeros: true,
+	}
+	if oa.rootLabel != "" {
+		oa.label = oa.explorePath()
+	}
+	var v any
+	// handle range key
+	if isIntervalKey(key) {
+		oa.sliceRange = parseInterval(key)
+		// no need to check canExplore
+		v = oa.Value()
+	} else {
+		// other keys
+		v = oa.Value()
+		if !canExplore(v) {
+			slog.Warn("[structexplorer] cannot explore this", "value", v, "path", oa.label, "type", fmt.Sprintf("%T", v))
+			return oa, false
+		}
+	}
+	oa.typeName = fmt.Sprintf("%T", v)
+	return oa, true
+}
+
 func (o objectAccess) isEmpty() bool {
 	return o.typeName == ""
 }
@@ -72,6 +129,17 @@ func (e *explorer) rootKeys() (list []string) {
 	return
 }
 
+func (e *explorer) rootLabels() (list []string) {
+	for _, row := range e.accessMap {
+		for _, access := range row {
+			if access.isRoot {
+				list = append(list, access.label)
+			}
+		}
+	}
+	return
+}
+
 func (e *explorer) rootAccessWithLabel(label string) (oa objectAccess, row int, col int, ok bool) {
 	for row, rows := range e.accessMap {
 		for col, each := range rows {
@@ -83,6 +151,17 @@ func (e *explorer) rootAccessWithLabel(label string) (oa objectAccess, row int,
 	return objectAccess{}, 0, 0, false
 }
 
+func (e *explorer) hasExplorePath(expr string) bool {
+	for _, row := range e.accessMap {
+		for _, access := range row {
+			if access.explorePath() == expr {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func newExplorerOnAll(labelValuePairs ...any) *explorer {
 	s := &explorer{
 		accessMap: map[int]map[int]objectAccess{},
@@ -106,8 +185,10 @@ func newExplorerOnAll(labelValuePairs ...any) *explorer {
 			object:    value,
 			path:      []string{""},
 			label:     label,
+			rootLabel: label,
 			hideZeros: true,
 			typeName:  fmt.Sprintf("%T", value),
+			locker:    lockerOf(value, nil),
 		}, Row(row))
 		row++
 	}
@@ -144,6 +225,27 @@ func (e *explorer) updateObjectAt(row, col int, updater func(access objectAccess
 	e.putObjectStartingAt(row, col, updater(old), Row(row))
 }
 
+// replaceRoot puts the access of a new root value at the cell of the root with the same label
+// and makes the cells explored from that root read their path from the new value.
+// pre: protected
+func (e *explorer) replaceRoot(row, col int, root objectAccess) {
+	e.putObjectAt(row, col, root)
+	for _, cols := range e.accessMap {
+		for c, each := range cols {
+			if each.isRoot || each.rootLabel != root.rootLabel {
+				continue
+			}
+			each.object = root.object
+			each.locker = root.locker
+			func() {
+				defer each.lock()()
+				each.typeName = fmt.Sprintf("%T", each.Value())
+			}()
+			cols[c] = each
+		}
+	}
+}
+
 func (e *explorer) putObjectAt(row, col int, access objectAccess) {
 	r, ok := e.accessMap[row]
 	if !ok {
@@ -169,25 +271,128 @@ func (e *explorer) putObjectStartingAt(row, col int, access objectAccess, option
 	e.putObjectStartingAt(newRow, newCol, access, option)
 }
 
+// pre: protected
+func (e *explorer) explorePath(expr string, options ...ExploreOption) error {
+	if expr == "" {
+		return nil
+	}
+	oa, row, col, err := e.accessForPath(expr)
+	if err != nil {
+		return err
+	}
+	if len(oa.path) == 0 {
+		// root is already explored
+		return nil
+	}
+	e.putObjectStartingAt(row, col, oa, placementOf(options, Row(row)))
+	return nil
+}
+
+// accessForPath returns a new objectAccess for the path expression and the location of its root.
+// The error explains why the path is invalid or cannot be explored.
+func (e *explorer) accessForPath(expr string) (oa objectAccess, row int, col int, err error) {
+	parsed, err := parsePath(expr, e.rootLabels())
+	if err != nil {
+		return oa, 0, 0, err
+	}
+	root, row, col, _ := e.rootAccessWithLabel(parsed.root)
+	defer root.lock()()
+	keys := parsed.keys
+	if n := len(keys); n > 0 && isIntervalKey(keys[n-1]) {
+		// accesses same object
+		oa.sliceRange = parseInterval(keys[n-1])
+		keys = keys[:n-1]
+	}
+	v, err := resolveAccessPath(root.object, keys)
+	if err != nil {
+		return oa, 0, 0, fmt.Errorf("invalid path %q: %w", expr, err)
+	}
+	oa.object = root.object
+	oa.path = parsed.keys
+	oa.label = formatPath(root.object, root.label, parsed.keys)
+	oa.rootLabel = root.label
+	oa.locker = root.locker
+	oa.hideZeros = true
+	oa.typeName = fmt.Sprintf("%T", v)
+	return oa, row, col, nil
+}
+
+// applyExploreQuery explores each value of the "explore" query parameter.
+// A value is either "<path>" or "<row>,<column>,<path>".
+// Paths that are already explored (at the same location) are skipped.
+// pre: protected
+func (e *explorer) applyExploreQuery(values []string) {
+	for _, each := range values {
+		expr, row, col, positioned := each, 0, 0, false
+		if parts := strings.SplitN(each, ",", 3); len(parts) == 3 {
+			r, rerr := strconv.Atoi(parts[0])
+			c, cerr := strconv.Atoi(parts[1])
+			if rerr == nil && cerr == nil {
+				expr, row, col, positioned = parts[2], r, c, true
+			}
+		}
+		oa, _, _, err := e.accessForPath(expr)
+		if err != nil {
+			slog.Warn("[structexplorer] cannot explore link", "err", err)
+			continue
+		}
+		if !positioned {
+			if !e.hasExplorePath(oa.label) {
+				e.explorePath(expr)
+			}
+			continue
+		}
+		if e.objectAt(row, col).explorePath() != oa.label {
+			e.explorePath(expr, RowColumn(row, col))
+		}
+	}
+}
+
+// buildIndexData builds the page with all cells.
+// pre: protected
 func (e *explorer) buildIndexData(b *indexDataBuilder) indexData {
-	// was it starting using Break?
-	b.data.IsBreaking = b.isBreaking
+	b.buildHeader(e)
+	b.buildCells(e.snapshot())
+	e.showZerosAt(b.showZeros)
+	return b.data
+}
+
+// cellSnapshot is a copy of the objectAccess at a location such that a page can be built without holding the lock.
+type cellSnapshot struct {
+	row, column int
+	access      objectAccess
+}
 
+// snapshot returns copies of all objectAccess values ordered by row and column.
+// pre: protected
+func (e *explorer) snapshot() (list []cellSnapshot) {
 	for row, each := range e.accessMap {
 		for col, access := range each {
-			info := b.build(row, col, access)
-			if info.entriesCount == 0 && info.hasZeros {
-				// toggle zero to have entries
-				e.updateObjectAt(row, col, func(access objectAccess) objectAccess {
-					access.hideZeros = false
-					return access
-				})
-				// rebuild
-				b.build(row, col, e.objectAt(row, col))
-			}
+			list = append(list, cellSnapshot{row: row, column: col, access: access})
 		}
 	}
-	return b.data
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].row == list[j].row {
+			return list[i].column < list[j].column
+		}
+		return list[i].row < list[j].row
+	})
+	return
+}
+
+// showZerosAt shows the zero values of the cells that have no entries otherwise.
+// Cells that were changed since the snapshot was taken are skipped.
+// pre: protected
+func (e *explorer) showZerosAt(cells []cellSnapshot) {
+	for _, each := range cells {
+		if e.objectAt(each.row, each.column).label != each.access.label {
+			continue
+		}
+		e.updateObjectAt(each.row, each.column, func(access objectAccess) objectAccess {
+			access.hideZeros = false
+			return access
+		})
+	}
 }
 
 func (e *explorer) removeNonRootObjects() {
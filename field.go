@@ -4,12 +4,11 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"unsafe"
-
-	"github.com/mitchellh/hashstructure/v2"
 )
 
 var maxFieldValueStringLength = 64
@@ -20,7 +19,7 @@ type fieldAccess struct {
 	owner any
 	// key is the name of field in struct
 	// or the string index in a slice or array
-	// or the encoded key hash in a map
+	// or the encoded key in a map, see reflectMapKeyToString
 	key   string
 	label string
 	Type  string
@@ -34,6 +33,12 @@ func (f fieldAccess) displayKey() string {
 }
 
 func (f fieldAccess) value() any {
+	if isTypeAssertionKey(f.key) {
+		if assertsType(f.owner, f.key[1:len(f.key)-1]) {
+			return f.owner
+		}
+		return nil
+	}
 	rv := reflect.ValueOf(f.owner)
 	if rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
 		// is a pointer
@@ -69,89 +74,15 @@ func (f fieldAccess) value() any {
 		}
 	}
 	if rv.Type().Kind() == reflect.Map {
-		// shortcut for string and int keys
-		keyType := rv.Type().Key()
-		if keyType.Kind() == reflect.String {
-			mv := rv.MapIndex(reflect.ValueOf(f.key))
-			// f.key could be a hash because the key contained a path separator
-			// then mv is not valid and the fallback is needed to get the actual key.
-			// todo: f.key != f.label test?
-			if mv.IsValid() {
-				return mv.Interface()
-			}
+		if isIntervalKey(f.key) {
+			return newMapPage(rv, parseInterval(f.key))
 		}
-		switch keyType.Kind() {
-		case reflect.Int:
-			i, _ := strconv.Atoi(f.key)
-			mv := rv.MapIndex(reflect.ValueOf(i))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Int8:
-			i, _ := strconv.ParseInt(f.key, 10, 8)
-			mv := rv.MapIndex(reflect.ValueOf(int8(i)))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Int16:
-			i, _ := strconv.ParseInt(f.key, 10, 16)
-			mv := rv.MapIndex(reflect.ValueOf(int16(i)))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Int32:
-			i, _ := strconv.ParseInt(f.key, 10, 32)
-			mv := rv.MapIndex(reflect.ValueOf(int32(i)))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Int64:
-			i, _ := strconv.ParseInt(f.key, 10, 64)
-			mv := rv.MapIndex(reflect.ValueOf(i))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Uint:
-			i, _ := strconv.ParseUint(f.key, 10, 0)
-			return rv.MapIndex(reflect.ValueOf(uint(i))).Interface()
-		case reflect.Uint8:
-			i, _ := strconv.ParseUint(f.key, 10, 8)
-			mv := rv.MapIndex(reflect.ValueOf(uint8(i)))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Uint16:
-			i, _ := strconv.ParseUint(f.key, 10, 16)
-			mv := rv.MapIndex(reflect.ValueOf(uint16(i)))
-			if mv.IsZero() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Uint32:
-			i, _ := strconv.ParseUint(f.key, 10, 32)
-			mv := rv.MapIndex(reflect.ValueOf(uint32(i)))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
-		case reflect.Uint64:
-			i, _ := strconv.ParseUint(f.key, 10, 64)
-			mv := rv.MapIndex(reflect.ValueOf(uint64(i)))
-			if mv.IsZero() || !mv.CanInterface() {
-				return nil
-			}
-			return mv.Interface()
+		key := reflectMapKeyFor(f.key, rv)
+		if !key.IsValid() {
+			return nil
 		}
-		// fallback: name is hash of key
-		key := stringToReflectMapKey(f.key, rv)
 		mv := rv.MapIndex(key)
-		if mv.IsZero() || !mv.CanInterface() {
+		if !mv.IsValid() || !mv.CanInterface() {
 			return nil
 		}
 		return mv.Interface()
@@ -164,12 +95,35 @@ func (f fieldAccess) value() any {
 		return nil
 	}
 	rf = reflect.NewAt(rf.Type(), unsafe.Pointer(rf.UnsafeAddr())).Elem()
+	if v, ok := readSyncValue(rf); ok {
+		return v
+	}
 	if rf.CanInterface() {
 		return rf.Interface()
 	}
 	return nil
 }
 
+// notReadable is shown instead of a value that cannot be read without a data race, such as a sync.Mutex.
+type notReadable string
+
+func (n notReadable) String() string { return string(n) }
+
+// readSyncValue returns the loaded value of a sync/atomic type and a notReadable for other sync types.
+// Copying these values races with goroutines that use them. rf must be addressable.
+func readSyncValue(rf reflect.Value) (any, bool) {
+	switch rf.Type().PkgPath() {
+	case "sync/atomic":
+		if load := rf.Addr().MethodByName("Load"); load.IsValid() && load.Type().NumIn() == 0 && load.Type().NumOut() == 1 {
+			return load.Call(nil)[0].Interface(), true
+		}
+		return notReadable(rf.Type().String()), true
+	case "sync":
+		return notReadable(rf.Type().String()), true
+	}
+	return nil, false
+}
+
 // pre: canExplore(v)
 // post: sorted by label
 func newFields(v any) []fieldAccess {
@@ -177,6 +131,9 @@ func newFields(v any) []fieldAccess {
 	if v == nil {
 		return list
 	}
+	if p, ok := v.(mapPage); ok {
+		return p.fields()
+	}
 	var rt reflect.Type
 	rv := reflect.ValueOf(v)
 	if rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
@@ -228,6 +185,10 @@ func newFields(v any) []fieldAccess {
 		return list
 	}
 	if rt.Kind() == reflect.Map {
+		// check if we need pages
+		if rv.Len() > mapPageLength {
+			return newMapPage(rv, zeroInterval).fields()
+		}
 		for _, key := range rv.MapKeys() {
 			list = append(list, fieldAccess{
 				Type:  rt.Elem().String(),
@@ -244,12 +205,104 @@ func newFields(v any) []fieldAccess {
 	return list
 }
 
+// sortEntries sorts by label in natural order; entries without label, such as struct fields, keep their order.
 func sortEntries(entries []fieldAccess) {
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].label < entries[j].label
+	sort.SliceStable(entries, func(i, j int) bool {
+		return naturalLess(entries[i].label, entries[j].label)
 	})
 }
 
+const (
+	sortByKey   = "key"
+	sortByValue = "value"
+	sortByType  = "type"
+)
+
+// isSortBy returns true if s is empty (declaration or key order) or one of the sortBy constants.
+func isSortBy(s string) bool {
+	return s == "" || s == sortByKey || s == sortByValue || s == sortByType
+}
+
+// sortFieldEntries sorts the entries of a cell by key, value or type; the order is kept if sortBy is empty.
+func sortFieldEntries(list []fieldEntry, sortBy string) {
+	var less func(a, b fieldEntry) bool
+	switch sortBy {
+	case sortByKey:
+		less = func(a, b fieldEntry) bool { return naturalLess(a.Label, b.Label) }
+	case sortByValue:
+		less = func(a, b fieldEntry) bool { return naturalLess(a.ValueString, b.ValueString) }
+	case sortByType:
+		less = func(a, b fieldEntry) bool {
+			if a.Type == b.Type {
+				return naturalLess(a.Label, b.Label)
+			}
+			return a.Type < b.Type
+		}
+	default:
+		return
+	}
+	sort.SliceStable(list, func(i, j int) bool { return less(list[i], list[j]) })
+}
+
+// entryFilter returns a function that matches the label and value of an entry.
+// A filter written as /expression/ is a regular expression, otherwise it is a case-insensitive substring.
+// An empty filter matches all entries.
+func entryFilter(filter string) (func(e fieldEntry) bool, error) {
+	if filter == "" {
+		return func(fieldEntry) bool { return true }, nil
+	}
+	if len(filter) > 1 && strings.HasPrefix(filter, "/") && strings.HasSuffix(filter, "/") {
+		re, err := regexp.Compile(filter[1 : len(filter)-1])
+		if err != nil {
+			return nil, err
+		}
+		return func(e fieldEntry) bool {
+			return re.MatchString(e.Label) || re.MatchString(e.ValueString)
+		}, nil
+	}
+	lower := strings.ToLower(filter)
+	return func(e fieldEntry) bool {
+		return strings.Contains(strings.ToLower(e.Label), lower) || strings.Contains(strings.ToLower(e.ValueString), lower)
+	}, nil
+}
+
+// naturalLess compares numbers by value and other strings with runs of digits compared as numbers,
+// such that "2" < "10" and "item2" < "item10".
+func naturalLess(a, b string) bool {
+	if fa, err := strconv.ParseFloat(a, 64); err == nil {
+		if fb, err := strconv.ParseFloat(b, 64); err == nil && fa != fb {
+			return fa < fb
+		}
+	}
+	for a != "" && b != "" {
+		da, db := digitPrefixLength(a), digitPrefixLength(b)
+		if da > 0 && db > 0 {
+			na, nb := strings.TrimLeft(a[:da], "0"), strings.TrimLeft(b[:db], "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[da:], b[db:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func digitPrefixLength(s string) int {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i
+}
+
 func applyFieldNamePadding(list []fieldEntry) []fieldEntry {
 	// longest field name
 	maxlength := 0
@@ -275,7 +328,7 @@ func valueAtAccessPath(value any, path []string) any {
 	if path[0] == "" {
 		return valueAtAccessPath(value, path[1:])
 	}
-	// field name, index or hash of map key
+	// field name, index or encoded map key
 	fa := fieldAccess{owner: value, key: path[0]}
 	// check for range
 	if isIntervalKey(fa.key) {
@@ -363,36 +416,6 @@ func ellipsis(s string) string {
 	return s
 }
 
-func reflectMapKeyToString(key reflect.Value) string {
-	// string and integer keys
-	if key.Kind() == reflect.String {
-		s := key.String()
-		// check for path separator
-		if !strings.Contains(s, ".") {
-			return s
-		}
-	}
-	if key.Kind() == reflect.Int || key.Kind() == reflect.Int8 || key.Kind() == reflect.Int16 || key.Kind() == reflect.Int32 || key.Kind() == reflect.Int64 {
-		return strconv.Itoa(int(key.Int()))
-	}
-	if key.Kind() == reflect.Uint || key.Kind() == reflect.Uint8 || key.Kind() == reflect.Uint16 || key.Kind() == reflect.Uint32 || key.Kind() == reflect.Uint64 {
-		return strconv.FormatUint(uint64(key.Uint()), 10)
-	}
-	// fallback to hash of key
-	hash, _ := hashstructure.Hash(key, hashstructure.FormatV2, nil)
-	return strconv.FormatUint(hash, 16)
-}
-func stringToReflectMapKey(hash string, m reflect.Value) reflect.Value {
-	for _, each := range m.MapKeys() {
-		cmp := reflectMapKeyToString(each)
-		if cmp == hash {
-			return each
-		}
-	}
-	// not found is actually a bug
-	return reflect.ValueOf(nil)
-}
-
 func isZeroPrintstring(s string) bool {
 	switch s {
 	case `""`, "0", "false", "nil", "0.000000", "0.000":
@@ -408,7 +431,26 @@ type interval struct {
 
 func (i interval) size() int { return i.to - i.from }
 
-func isIntervalKey(k string) bool { return strings.Contains(k, ":") }
+// isIntervalKey returns true for keys such as 10:20; encoded map keys can also contain ':'.
+func isIntervalKey(k string) bool {
+	from, to, ok := strings.Cut(k, ":")
+	if !ok {
+		return false
+	}
+	return isDigits(from) && isDigits(to)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
 
 func makeIntervalKey(from, to int) string {
 	return fmt.Sprintf("%d:%d", from, to)
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -133,7 +135,7 @@ func TestMapKeyUint32(t *testing.T) {
 func TestMapKeyWithDotAndBack(t *testing.T) {
 	m := map[string]int{".": 2}
 	ks := reflectMapKeyToString(reflect.ValueOf("."))
-	if got, want := ks, "817af0b8cb6ee7c"; got != want {
+	if got, want := ks, `"."`; got != want {
 		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
 	}
 	rm := reflect.ValueOf(m)
@@ -503,3 +505,49 @@ func TestPointerToInnerTypedSlice(t *testing.T) {
 	l := newFields(v)
 	t.Log(l)
 }
+
+func TestNaturalLess(t *testing.T) {
+	for _, each := range []struct {
+		a, b string
+		less bool
+	}{
+		{"2", "10", true},
+		{"10", "2", false},
+		{"-10", "-1", true},
+		{"1.5", "10", true},
+		{"item2", "item10", true},
+		{"item10", "item2", false},
+		{"a", "b", true},
+		{"a", "a", false},
+		{"a", "ab", true},
+		{"x02", "x2", false},
+	} {
+		if got, want := naturalLess(each.a, each.b), each.less; got != want {
+			t.Errorf("%s < %s got [%[3]v:%[3]T] want [%[4]v:%[4]T]", each.a, each.b, got, want)
+		}
+	}
+}
+
+func TestEntryFilterInvalidRegexp(t *testing.T) {
+	if _, err := entryFilter("/[/"); err == nil {
+		t.Error("error expected")
+	}
+	if _, err := entryFilter("["); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFieldSyncValues(t *testing.T) {
+	type guarded struct {
+		mu    sync.Mutex
+		count atomic.Int64
+	}
+	g := &guarded{}
+	g.count.Store(3)
+	if got, want := printString((fieldAccess{owner: g, key: "mu"}).value()), "sync.Mutex"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := (fieldAccess{owner: g, key: "count"}).value(), any(int64(3)); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
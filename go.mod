@@ -2,4 +2,4 @@ module github.com/emicklei/structexplorer
 
 go 1.22
 
-require github.com/mitchellh/hashstructure/v2 v2.0.2
+require gopkg.in/yaml.v3 v3.0.1
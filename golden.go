@@ -0,0 +1,137 @@
+package structexplorer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoldenOption is a type for the options that can be passed to GoldenText.
+type GoldenOption struct {
+	depth  int
+	redact []string
+}
+
+// GoldenDepth sets the maximum number of fields between the value and the fields that are listed.
+// The default is 10.
+func GoldenDepth(depth int) GoldenOption {
+	return GoldenOption{depth: depth}
+}
+
+// Redact replaces the value of fields that match any of the keys or path expressions, e.g. "CreatedAt" or "order.Lines[0].ID".
+// The path expressions start with the name of the value.
+func Redact(keysOrPaths ...string) GoldenOption {
+	return GoldenOption{redact: keysOrPaths}
+}
+
+// GoldenText returns the value as a text tree, as it would be explored in the Browser, with one field per line.
+// Map keys are sorted and pointers are shown as @1, @2,... in the order in which they are listed,
+// such that the text does not depend on addresses. See structexplorertest.AssertGolden to compare it with a file.
+func GoldenText(name string, value any, opts ...GoldenOption) string {
+	return renderGolden(name, value, opts...)
+}
+
+// renderGolden returns the text tree of the value with one field per line, indented by its depth.
+func renderGolden(name string, value any, opts ...GoldenOption) string {
+	r := &goldenRenderer{
+		depth:    10,
+		redact:   map[string]bool{},
+		pointers: map[goldenPointer]int{},
+		b:        new(strings.Builder),
+	}
+	for _, each := range opts {
+		if each.depth > 0 {
+			r.depth = each.depth
+		}
+		for _, key := range each.redact {
+			r.redact[key] = true
+		}
+	}
+	r.render(0, name, name, safePrintString(value), value, 0)
+	return r.b.String()
+}
+
+type goldenRenderer struct {
+	depth    int
+	redact   map[string]bool
+	pointers map[goldenPointer]int // placeholder number of each listed pointer
+	b        *strings.Builder
+}
+
+type goldenPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// render writes the line of a field and the lines of its fields.
+// offset is added to the indices of the elements of a range of a slice or array.
+func (r *goldenRenderer) render(level int, label, path, valueString string, value any, offset int) {
+	r.b.WriteString(strings.Repeat("  ", level))
+	r.b.WriteString(label + ": ")
+	if r.redact[label] || r.redact[path] {
+		r.b.WriteString("<redacted>\n")
+		return
+	}
+	r.b.WriteString(valueString)
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		p := goldenPointer{typ: rv.Type(), ptr: rv.Pointer()}
+		if n, ok := r.pointers[p]; ok {
+			// listed before, also for cycles
+			fmt.Fprintf(r.b, " -> @%d\n", n)
+			return
+		}
+		r.pointers[p] = len(r.pointers) + 1
+		fmt.Fprintf(r.b, " @%d", r.pointers[p])
+	}
+	if value == nil || !canExplore(value) {
+		r.b.WriteString("\n")
+		return
+	}
+	if level == r.depth {
+		r.b.WriteString(" ...\n")
+		return
+	}
+	r.b.WriteString("\n")
+	for _, each := range r.fields(value) {
+		key, fieldLabel := each.key, each.displayKey()
+		if offset > 0 {
+			ik, _ := strconv.Atoi(key)
+			key = strconv.Itoa(ik + offset)
+			fieldLabel = key
+		}
+		fieldPath := path + formatPathKey(each.owner, key)
+		childOffset := 0
+		if isIntervalKey(key) {
+			// a range is not part of the path of its elements, see formatPath
+			fieldPath = path
+			if k := kindOf(each.owner); k == reflect.Slice || k == reflect.Array {
+				childOffset = parseInterval(key).from
+			}
+		}
+		fieldValue, _ := safeFieldValue(each)
+		r.render(level+1, fieldLabel, fieldPath, safeComputeValueString(each), fieldValue, childOffset)
+	}
+}
+
+// fields returns the fields of the value in the order of the Browser;
+// entries of maps with equal labels, such as pointer keys, are ordered by their value.
+func (r *goldenRenderer) fields(value any) []fieldAccess {
+	list := newFields(value)
+	// equal labels are adjacent after sortEntries
+	for from := 0; from < len(list); {
+		to := from + 1
+		for to < len(list) && list[from].label != "" && list[to].label == list[from].label {
+			to++
+		}
+		if to-from > 1 {
+			run := list[from:to]
+			sort.SliceStable(run, func(i, j int) bool {
+				return safeComputeValueString(run[i]) < safeComputeValueString(run[j])
+			})
+		}
+		from = to
+	}
+	return list
+}
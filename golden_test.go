@@ -0,0 +1,59 @@
+package structexplorer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type goldenOrder struct {
+	ID        int
+	CreatedAt time.Time
+	Customer  *goldenCustomer
+	Lines     []goldenLine
+	Tags      map[string]bool
+}
+
+type goldenCustomer struct {
+	Name   string
+	Orders []*goldenOrder
+}
+
+type goldenLine struct {
+	SKU      string
+	Quantity int
+}
+
+func newGoldenOrder() *goldenOrder {
+	order := &goldenOrder{
+		ID:        7,
+		CreatedAt: time.Now(),
+		Lines:     []goldenLine{{SKU: "x", Quantity: 2}, {SKU: "y"}},
+		Tags:      map[string]bool{"item10": true, "item2": false, "gift": true},
+	}
+	order.Customer = &goldenCustomer{Name: "alice", Orders: []*goldenOrder{order}}
+	return order
+}
+
+func TestGoldenTextDepth(t *testing.T) {
+	got := GoldenText("order", newGoldenOrder(), GoldenDepth(1), Redact("CreatedAt"))
+	want := `order: *structexplorer.goldenOrder @1
+  ID: 7
+  CreatedAt: <redacted>
+  Customer: *structexplorer.goldenCustomer @2 ...
+  Lines: []structexplorer.goldenLine (2) ...
+  Tags: map[string]bool (3) ...
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGoldenTextLargeSlice(t *testing.T) {
+	list := make([]int, 60)
+	list[55] = 1
+	got := GoldenText("list", list)
+	if !strings.Contains(got, "  50:60: []int (10)\n    50: 0\n") || !strings.Contains(got, "    55: 1\n") {
+		t.Errorf("got\n%s", got)
+	}
+}
@@ -4,17 +4,34 @@ import (
 	"fmt"
 	"html/template"
 	"log/slog"
+	"reflect"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type indexDataBuilder struct {
 	data       indexData
-	seq        int
 	notLive    bool
-	isBreaking bool   // service is started with Break(...)
-	selectID   string // id of the added fieldList (select element)
+	isBreaking bool         // service is started with Break(...)
+	breaks     []breakEntry // all paused goroutines when isBreaking
+	stack      []stackFrame // of the paused goroutine when isBreaking
+	// all locations that called a Break function
+	breakSites        []breakSiteEntry
+	breakSitesEnabled bool
+	watches           []watchEntry
+	tracks            []trackEntry
+	debugLinks        []debugLink
+	layoutKey         string // changes when the service restarts, empty if not live
+	workspace         string
+	workspaces        []string
+	selectID          string // id of the added fieldList (select element)
+	// if set then cells built after the deadline or after this number of entries are loaded by the Browser
+	deadline   time.Time
+	maxEntries int
+	entries    int            // number of entries built so far
+	showZeros  []cellSnapshot // cells that only have entries with zero values shown
 }
 
 func newIndexDataBuilder() *indexDataBuilder {
@@ -26,23 +43,106 @@ func newIndexDataBuilder() *indexDataBuilder {
 	return b
 }
 
-type cellInfo struct {
-	entriesCount int
-	hasZeros     bool
+// buildHeader sets all data of the page except the cells.
+// pre: protected
+func (b *indexDataBuilder) buildHeader(e *explorer) {
+	// was it starting using Break?
+	b.data.IsBreaking = b.isBreaking
+	b.data.Breaks = b.breaks
+	for i, each := range b.stack {
+		b.data.Stack = append(b.data.Stack, stackFrameEntry{
+			Index:     i,
+			Function:  each.Function,
+			Location:  each.location(),
+			File:      each.File,
+			HasLocals: len(each.Locals) > 0,
+		})
+	}
+	b.data.BreakSites = b.breakSites
+	b.data.BreakSitesEnabled = b.breakSitesEnabled
+	b.data.Watches = b.watches
+	b.data.Tracks = b.tracks
+	b.data.DebugLinks = b.debugLinks
+	b.data.Workspace = b.workspace
+	b.data.Workspaces = b.workspaces
+	if b.layoutKey != "" && !b.isBreaking {
+		b.data.LayoutKey = b.layoutKey
+		b.data.Layout = e.layout()
+		b.data.ShareQuery = shareQuery(b.data.Layout)
+	}
 }
 
-func (b *indexDataBuilder) build(row, column int, access objectAccess) cellInfo {
-	// check room
+// buildCells builds the cells in order; this does not require the lock.
+// Cells that exceed the time or size budget are left for the Browser to load.
+func (b *indexDataBuilder) buildCells(cells []cellSnapshot) {
+	for _, each := range cells {
+		if b.overBudget() {
+			b.buildLazy(each.row, each.column, each.access)
+			continue
+		}
+		b.buildCell(each)
+	}
+}
+
+// buildCell builds one cell and shows its zero values if it would have no entries otherwise.
+func (b *indexDataBuilder) buildCell(cell cellSnapshot) {
+	info := b.build(cell.row, cell.column, cell.access)
+	if info.entriesCount == 0 && info.hasZeros {
+		// toggle zero to have entries
+		cell.access.hideZeros = false
+		b.showZeros = append(b.showZeros, cell)
+		// rebuild
+		info = b.build(cell.row, cell.column, cell.access)
+	}
+	b.entries += info.entriesCount
+}
+
+func (b *indexDataBuilder) overBudget() bool {
+	if !b.deadline.IsZero() && time.Now().After(b.deadline) {
+		return true
+	}
+	return b.maxEntries > 0 && b.entries >= b.maxEntries
+}
+
+// buildLazy adds a placeholder for a cell that is loaded by the Browser.
+func (b *indexDataBuilder) buildLazy(row, column int, access objectAccess) {
+	b.ensureCell(row, column)
+	b.data.Rows[row].Cells[column] = fieldList{
+		Row:    row,
+		Column: column,
+		Label:  template.HTML(template.HTMLEscapeString(access.label)),
+		Type:   access.typeName,
+		IsLazy: true,
+	}
+}
+
+func (b *indexDataBuilder) ensureCell(row, column int) {
 	for len(b.data.Rows) <= row {
 		b.data.Rows = append(b.data.Rows, tableRow{})
 	}
 	for len(b.data.Rows[row].Cells) <= column {
 		b.data.Rows[row].Cells = append(b.data.Rows[row].Cells, fieldList{})
 	}
+}
+
+type cellInfo struct {
+	entriesCount int
+	hasZeros     bool
+}
+
+func (b *indexDataBuilder) build(row, column int, access objectAccess) cellInfo {
+	defer access.lock()()
+	b.ensureCell(row, column)
 	// copy fields into entries
 	hasZeros := false
 	entries := []fieldEntry{}
 	currentValue := access.Value()
+	// entries of a map page have map keys, not indices
+	page, isPage := currentValue.(mapPage)
+	basePath := access.explorePath()
+	if basePath == "" {
+		basePath = access.label
+	}
 	for _, each := range newFields(currentValue) {
 		valString := safeComputeValueString(each)
 		if isZeroPrintstring(valString) {
@@ -55,7 +155,7 @@ func (b *indexDataBuilder) build(row, column int, access objectAccess) cellInfo
 		entryKey := each.key
 		// if the access is part of a large slice or array
 		// then offset both the key and label
-		if access.sliceRange.size() > 1 {
+		if access.sliceRange.size() > 1 && !isPage {
 			ik, _ := strconv.Atoi(entryKey)
 			label = strconv.Itoa(ik + access.sliceRange.from)
 			entryKey = label
@@ -63,28 +163,45 @@ func (b *indexDataBuilder) build(row, column int, access objectAccess) cellInfo
 		entries = append(entries, fieldEntry{
 			Label:       label,
 			Key:         entryKey,
+			Path:        basePath + formatPathKey(each.owner, entryKey),
 			Type:        each.Type,
 			ValueString: valString,
 		})
 	}
+	totalCount := len(entries)
+	if match, err := entryFilter(access.filter); err == nil {
+		filtered := entries[:0]
+		for _, each := range entries {
+			if match(each) {
+				filtered = append(filtered, each)
+			}
+		}
+		entries = filtered
+	}
+	sortFieldEntries(entries, access.sortBy)
 	entries = applyFieldNamePadding(entries)
 	size := computeSizeOfWidestEntry(entries)
 	// adjust label so that table cell width is used to display select options
-	fieldListLabel := access.label
-	if size > len(fieldListLabel) {
+	fieldListLabel := template.HTMLEscapeString(access.label)
+	if size > len(access.label) {
 		fieldListLabel += strings.Repeat("&nbsp;", size-len(access.label))
 	}
-	newSelectID := fmt.Sprintf("id%d", b.seq)
+	// unique on the page, also for cells loaded by the Browser
+	newSelectID := fmt.Sprintf("id%d-%d", row, column)
 	typ := access.typeName
 	if typ == "" {
 		// when using Follow, the type is not set/known
 		typ = fmt.Sprintf("%T", currentValue)
 	}
+	if isPage {
+		typ = page.mapType()
+	}
 	b.data.Rows[row].Cells[column] = fieldList{
 		Row:        row,
 		Column:     column,
 		Path:       strings.Join(access.path, "."),
 		Label:      template.HTML(fieldListLabel),
+		Name:       access.label,
 		Fields:     entries,
 		Type:       typ,
 		IsRoot:     access.isRoot,
@@ -92,10 +209,32 @@ func (b *indexDataBuilder) build(row, column int, access objectAccess) cellInfo
 		SelectSize: len(entries),
 		SelectID:   newSelectID,
 		NotLive:    b.notLive,
+
+		ExplorePath: access.explorePath(),
+		SortBy:      access.sortBy,
+		Filter:      access.filter,
+		TotalCount:  totalCount,
+		Length:      lengthOf(currentValue),
 	}
 	b.selectID = newSelectID
-	b.seq++
-	return cellInfo{entriesCount: len(entries), hasZeros: hasZeros}
+	// entries hidden by the filter are counted, the filter must be cleared by the user
+	return cellInfo{entriesCount: totalCount, hasZeros: hasZeros}
+}
+
+// lengthOf returns the number of elements of a map, slice or array and of the map of a page; 0 otherwise.
+func lengthOf(v any) int {
+	if p, ok := v.(mapPage); ok {
+		return p.m.Len()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv.Len()
+	}
+	return 0
 }
 
 func safeComputeValueString(fa fieldAccess) string {
@@ -69,3 +69,60 @@ func TestBuildSliceWithInterval(t *testing.T) {
 		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
 	}
 }
+
+func TestBuildMapSortedNaturally(t *testing.T) {
+	oa := objectAccess{
+		object: map[int]string{10: "b", 2: "c", -1: "a"},
+		path:   []string{""},
+	}
+	b := newIndexDataBuilder()
+	b.build(0, 0, oa)
+	fields := b.data.Rows[0].Cells[0].Fields
+	if got, want := fields[0].Label+fields[1].Label+fields[2].Label, "-1210"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestBuildSortedAndFiltered(t *testing.T) {
+	oa := objectAccess{
+		object: map[string]int{"apple": 30, "banana": 4, "cherry": 100, "avocado": 7},
+		path:   []string{""},
+		sortBy: sortByValue,
+		filter: "a",
+	}
+	b := newIndexDataBuilder()
+	b.build(0, 0, oa)
+	cell := b.data.Rows[0].Cells[0]
+	if got, want := len(cell.Fields), 3; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := cell.TotalCount, 4; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := cell.Fields[0].Label, `"banana"`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	oa.filter = `/^"a/`
+	oa.sortBy = sortByKey
+	b = newIndexDataBuilder()
+	b.build(0, 0, oa)
+	cell = b.data.Rows[0].Cells[0]
+	if got, want := len(cell.Fields), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := cell.Fields[1].Label, `"avocado"`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestBuildEscapesLabel(t *testing.T) {
+	oa := objectAccess{
+		object: []int{1},
+		label:  "<b>list</b>",
+	}
+	b := newIndexDataBuilder()
+	b.build(0, 0, oa)
+	if got, want := string(b.data.Rows[0].Cells[0].Label), "&lt;b&gt;list&lt;/b&gt;"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -18,27 +18,110 @@ type (
 		Script     template.JS
 		Style      template.CSS
 		IsBreaking bool
+		Breaks     []breakEntry
+		Stack      []stackFrameEntry // of the paused goroutine
+		// locations in code that called a Break function
+		BreakSites        []breakSiteEntry
+		BreakSitesEnabled bool
+		// path expressions with their current value
+		Watches []watchEntry
+		// sampled numeric values with their recent history
+		Tracks []trackEntry
+		// pairs of values compared field by field
+		Comparisons []compareEntry
+		// to save and restore the layout in the browser
+		Layout    []layoutCell
+		LayoutKey string
+		// URL query to share the layout
+		ShareQuery string
+		// name of the current workspace and all names
+		Workspace  string
+		Workspaces []string
+		// endpoints of expvar and pprof, when registered using RegisterDebug
+		DebugLinks []debugLink
+	}
+	debugLink struct {
+		Name string
+		URL  string
+	}
+	breakEntry struct {
+		Path        string
+		GoroutineID int64
+		Caller      string
+		Labels      string
+		IsCurrent   bool
+		Deadline    int64 // Unix milliseconds at which the goroutine resumes automatically, 0 if none
+	}
+	stackFrameEntry struct {
+		Index     int
+		Function  string
+		Location  string // file:line
+		File      string
+		HasLocals bool
+	}
+	watchEntry struct {
+		Label        string
+		Path         string
+		Value        string
+		IsError      bool
+		ChangedAt    string // time of day
+		ChangedSince string // duration
+	}
+	trackEntry struct {
+		Path           string
+		Points         string // of the sparkline polyline
+		Min, Max, Last string
+		Count          int    // number of samples
+		Error          string // of the last sample
+	}
+	compareEntry struct {
+		Left, Right string // path expressions
+		Align       bool   // elements of slices are aligned
+		Rows        []compareRow
+		Differences int
+		Error       string
+	}
+	compareRow struct {
+		Padding     template.HTML // indentation of the level
+		Label       string
+		Left, Right string
+		Kind        string // equal, changed, missing or extra
+	}
+	breakSiteEntry struct {
+		Location string
+		Hits     int
+		Enabled  bool
 	}
 	tableRow struct {
 		Cells []fieldList
 	}
 	fieldList struct {
-		Label      template.HTML
-		Path       string
-		Row        int
-		Column     int
-		Type       string
-		IsRoot     bool
-		HasZeros   bool
-		Access     string
+		Label    template.HTML // padded to the width of the widest entry
+		Name     string        // label without padding
+		Path     string
+		Row      int
+		Column   int
+		Type     string
+		IsRoot   bool
+		HasZeros bool
+		Access   string
+		// dotted path that can be passed to ExplorePath, empty if not from a root
+		ExplorePath string
+		// how the fields are sorted and filtered, see objectAccess
+		SortBy     string
+		Filter     string
+		TotalCount int // number of fields before filtering
+		Length     int // number of elements of a map, slice or array
 		Fields     []fieldEntry
 		SelectSize int
 		SelectID   string
 		NotLive    bool
+		IsLazy     bool // entries are loaded by the Browser
 	}
 	fieldEntry struct {
 		Label       string
 		Key         string
+		Path        string // path expression of the value
 		Type        string
 		ValueString string // printstring(fieldAcess.value())
 		Padding     template.HTML
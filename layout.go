@@ -0,0 +1,141 @@
+package structexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"sort"
+)
+
+// layoutCell is the serializable state of one objectAccess on the page.
+type layoutCell struct {
+	Row       int      `json:"row"`
+	Column    int      `json:"column"`
+	IsRoot    bool     `json:"isRoot,omitempty"`
+	Root      string   `json:"root"`
+	Path      []string `json:"path"`
+	Label     string   `json:"label"`
+	HideZeros bool     `json:"hideZeros"`
+	SortBy    string   `json:"sortBy,omitempty"`
+	Filter    string   `json:"filter,omitempty"`
+}
+
+// layout returns the cells that can be restored, ordered by row and column.
+// Cells that do not start from a root, such as stack frames, are left out.
+func (e *explorer) layout() (list []layoutCell) {
+	for row, each := range e.accessMap {
+		for col, access := range each {
+			if access.rootLabel == "" {
+				continue
+			}
+			list = append(list, layoutCell{
+				Row:       row,
+				Column:    col,
+				IsRoot:    access.isRoot,
+				Root:      access.rootLabel,
+				Path:      access.path,
+				Label:     access.label,
+				HideZeros: access.hideZeros,
+				SortBy:    access.sortBy,
+				Filter:    access.filter,
+			})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Row == list[j].Row {
+			return list[i].Column < list[j].Column
+		}
+		return list[i].Row < list[j].Row
+	})
+	return
+}
+
+// restoreLayout puts the cells back by resolving each path against the roots.
+// Cells for roots only restore their settings; paths that no longer resolve are dropped.
+func (e *explorer) restoreLayout(cells []layoutCell) {
+	for _, each := range cells {
+		root, row, col, ok := e.rootAccessWithLabel(each.Root)
+		if !ok {
+			continue
+		}
+		if each.IsRoot {
+			e.updateObjectAt(row, col, func(access objectAccess) objectAccess {
+				access.hideZeros = each.HideZeros
+				access.sortBy = each.SortBy
+				access.filter = each.Filter
+				return access
+			})
+			continue
+		}
+		oa := objectAccess{
+			object:    root.object,
+			path:      each.Path,
+			label:     each.Label,
+			rootLabel: root.label,
+			locker:    root.locker,
+			hideZeros: each.HideZeros,
+			sortBy:    each.SortBy,
+			filter:    each.Filter,
+		}
+		if existing := e.objectAt(each.Row, each.Column); existing.rootLabel == each.Root && existing.label == each.Label {
+			// already restored
+			continue
+		}
+		unlock := oa.lock()
+		var v any
+		if n := len(each.Path); n > 0 && isIntervalKey(each.Path[n-1]) {
+			oa.sliceRange = parseInterval(each.Path[n-1])
+			v = valueAtAccessPath(root.object, each.Path[:n-1])
+		} else {
+			v = oa.Value()
+		}
+		explorable := v != nil && canExplore(v)
+		unlock()
+		if !explorable {
+			slog.Debug("[structexplorer] dropped layout cell", "root", each.Root, "path", each.Path)
+			continue
+		}
+		oa.typeName = fmt.Sprintf("%T", v)
+		e.putObjectStartingAt(each.Row, each.Column, oa, Row(each.Row))
+	}
+}
+
+// saveLayoutFile writes the layout as JSON.
+func (e *explorer) saveLayoutFile(name string) error {
+	data, err := json.MarshalIndent(e.layout(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0644)
+}
+
+// loadLayoutFile reads and restores the layout; a missing file is not an error.
+func (e *explorer) loadLayoutFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	cells := []layoutCell{}
+	if err := json.Unmarshal(data, &cells); err != nil {
+		return err
+	}
+	e.restoreLayout(cells)
+	return nil
+}
+
+// shareQuery returns the URL query that reconstructs the non-root cells of the layout.
+func shareQuery(cells []layoutCell) string {
+	query := url.Values{}
+	for _, each := range cells {
+		if each.IsRoot {
+			continue
+		}
+		query.Add("explore", fmt.Sprintf("%d,%d,%s", each.Row, each.Column, each.Label))
+	}
+	return query.Encode()
+}
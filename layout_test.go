@@ -0,0 +1,86 @@
+package structexplorer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type layoutThing struct {
+	Name  string
+	Items []int
+	Next  *layoutThing
+}
+
+func TestLayoutRestore(t *testing.T) {
+	thing := &layoutThing{Name: "a", Items: []int{1, 2}, Next: &layoutThing{Name: "b"}}
+	s := NewService("thing", thing).(*service)
+	s.ExplorePath("thing.Next", RowColumn(0, 1))
+	s.ExplorePath("thing.Items", RowColumn(1, 0))
+	s.explorer.updateObjectAt(0, 0, func(access objectAccess) objectAccess {
+		access.hideZeros = false
+		return access
+	})
+	cells := s.explorer.layout()
+	if got, want := len(cells), 3; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// restart with a value that no longer has Next
+	other := NewService("thing", &layoutThing{Items: []int{3}}).(*service)
+	other.explorer.restoreLayout(cells)
+	if got, want := other.explorer.objectAt(1, 0).label, "thing.Items"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := other.explorer.objectAt(0, 1).isEmpty(), true; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := other.explorer.objectAt(0, 0).hideZeros, false; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// restoring twice does not duplicate
+	other.explorer.restoreLayout(cells)
+	if got, want := len(other.explorer.layout()), 2; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestLayoutFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "layout.json")
+	thing := &layoutThing{Name: "a", Next: &layoutThing{Name: "b"}}
+	s := NewService("thing", thing).(*service)
+	s.ExplorePath("thing.Next")
+	if err := s.explorer.saveLayoutFile(name); err != nil {
+		t.Fatal(err)
+	}
+	other := NewService("thing", thing).(*service)
+	if err := other.explorer.loadLayoutFile(name); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := other.explorer.objectAt(0, 1).label, "thing.Next"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if err := other.explorer.loadLayoutFile(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLayoutRestoreSortAndFilter(t *testing.T) {
+	thing := &layoutThing{Items: []int{3, 1, 2}}
+	s := NewService("thing", thing).(*service)
+	s.ExplorePath("thing.Items", RowColumn(0, 1))
+	s.explorer.updateObjectAt(0, 1, func(access objectAccess) objectAccess {
+		access.sortBy = sortByValue
+		access.filter = "/[12]/"
+		return access
+	})
+	other := NewService("thing", thing).(*service)
+	other.explorer.restoreLayout(s.explorer.layout())
+	oa := other.explorer.objectAt(0, 1)
+	if got, want := oa.sortBy, sortByValue; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := oa.filter, "/[12]/"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -0,0 +1,350 @@
+package structexplorer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// reflectMapKeyToString returns a readable encoding of a map key that is used in access paths.
+//
+//	string        alice or "alice@example.com" if it contains one of .:"()[]
+//	int, uint     42
+//	bool          true
+//	float         1.5
+//	pointer       0xc000012345 or nil
+//	struct        {Name:"alice",Age:42}
+//	array         [1,2,3]
+//	interface     int(42)
+//
+// Strings, integers, bools and floats are converted back without looking at the keys of the map,
+// other keys are looked up using a mapKeyIndex.
+func reflectMapKeyToString(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return encodeStringMapKey(key.String())
+	}
+	b := new(strings.Builder)
+	writeMapKey(b, key)
+	return b.String()
+}
+
+func writeMapKey(b *strings.Builder, key reflect.Value) {
+	switch key.Kind() {
+	case reflect.String:
+		b.WriteString(strconv.Quote(key.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(key.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(key.Uint(), 10))
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(key.Bool()))
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(strconv.FormatFloat(key.Float(), 'g', -1, key.Type().Bits()))
+	case reflect.Complex64, reflect.Complex128:
+		b.WriteString(strconv.FormatComplex(key.Complex(), 'g', -1, key.Type().Bits()))
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		if key.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		fmt.Fprintf(b, "0x%x", key.Pointer())
+	case reflect.Struct:
+		b.WriteString("{")
+		for i := range key.NumField() {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(key.Type().Field(i).Name)
+			b.WriteString(":")
+			writeMapKey(b, key.Field(i))
+		}
+		b.WriteString("}")
+	case reflect.Array:
+		b.WriteString("[")
+		for i := range key.Len() {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			writeMapKey(b, key.Index(i))
+		}
+		b.WriteString("]")
+	case reflect.Interface:
+		if key.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		elem := key.Elem()
+		b.WriteString(elem.Type().String())
+		b.WriteString("(")
+		writeMapKey(b, elem)
+		b.WriteString(")")
+	default:
+		// not comparable so cannot be a key
+		b.WriteString(key.Type().String())
+	}
+}
+
+// encodeStringMapKey returns the string unless it can be confused with other keys in an access path;
+// then it returns the quoted string.
+func encodeStringMapKey(s string) string {
+	if s == "" || strings.ContainsAny(s, `.:"()[]`) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func isQuotedKey(k string) bool {
+	return len(k) > 1 && k[0] == '"' && k[len(k)-1] == '"'
+}
+
+// reflectMapKeyFor returns the key value for a map from its string representation.
+// It returns an invalid value if the key cannot be converted.
+func reflectMapKeyFor(key string, m reflect.Value) reflect.Value {
+	keyType := m.Type().Key()
+	switch keyType.Kind() {
+	case reflect.String:
+		if isQuotedKey(key) {
+			if s, err := strconv.Unquote(key); err == nil {
+				key = s
+			}
+		}
+		return reflect.ValueOf(key).Convert(keyType)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(i).Convert(keyType)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(key, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(i).Convert(keyType)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(b).Convert(keyType)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(key, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(f).Convert(keyType)
+	}
+	return stringToReflectMapKey(key, m)
+}
+
+// stringToReflectMapKey returns the key of the map for which the encoding is equal to the string.
+// It returns an invalid value if no such key exists.
+func stringToReflectMapKey(encoded string, m reflect.Value) reflect.Value {
+	if m.Len() < mapKeyIndexMinLength {
+		return scanMapKeys(encoded, m)
+	}
+	return mapKeyIndexes.lookup(encoded, m)
+}
+
+func scanMapKeys(encoded string, m reflect.Value) reflect.Value {
+	iter := m.MapRange()
+	for iter.Next() {
+		if reflectMapKeyToString(iter.Key()) == encoded {
+			return iter.Key()
+		}
+	}
+	return reflect.Value{}
+}
+
+// mapKeyIndexMinLength is the number of entries a map must have before its keys are indexed.
+var mapKeyIndexMinLength = 64
+
+// maxMapKeyIndexes is the number of maps for which an index is kept.
+var maxMapKeyIndexes = 16
+
+var mapKeyIndexes = &mapKeyIndexCache{indexes: map[mapKeyIndexID]*mapKeyIndex{}}
+
+// mapKeyIndexID identifies a map; the type is included because the memory of a map that was freed
+// can be reused by another map. The keys of a cached index are checked against the map before use.
+type mapKeyIndexID struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+}
+
+func mapKeyIndexIDOf(m reflect.Value) mapKeyIndexID {
+	return mapKeyIndexID{ptr: m.UnsafePointer(), typ: m.Type()}
+}
+
+// mapKeySamples is the number of sorted keys, evenly spaced, that must be in the map to use its sorted keys again.
+var mapKeySamples = 64
+
+// mapKeyCheckInterval is the time after which all sorted keys must be in the map to use them again.
+var mapKeyCheckInterval = 2 * time.Second
+
+// mapKeyIndex maps the encodings of the keys of one map to its keys and holds the keys in sorted order.
+// Both are computed when first needed.
+type mapKeyIndex struct {
+	length  int
+	keys    map[string]reflect.Value
+	sorted  []reflect.Value
+	checked time.Time // when all sorted keys were last found in the map
+}
+
+// hasSortedKeysOf returns true if the sorted keys are the keys of the map, which can have changed
+// without changing its length. To not read all keys of a large map each time, only a sample of the keys is
+// checked unless the last check of all keys is older than mapKeyCheckInterval; see also newMapPage.
+func (idx *mapKeyIndex) hasSortedKeysOf(m reflect.Value, now time.Time) bool {
+	if idx.sorted == nil || len(idx.sorted) != m.Len() {
+		return false
+	}
+	// the keys are distinct so all must be present
+	step := max(1, len(idx.sorted)/mapKeySamples)
+	for i := 0; i < len(idx.sorted); i += step {
+		if !m.MapIndex(idx.sorted[i]).IsValid() {
+			return false
+		}
+	}
+	if step == 1 || now.Sub(idx.checked) < mapKeyCheckInterval {
+		return true
+	}
+	if !hasMapKeys(m, idx.sorted) {
+		return false
+	}
+	idx.checked = now
+	return true
+}
+
+// hasMapKeys returns true if all keys are in the map.
+func hasMapKeys(m reflect.Value, keys []reflect.Value) bool {
+	for _, each := range keys {
+		if !m.MapIndex(each).IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+func (idx *mapKeyIndex) buildKeys(m reflect.Value) {
+	idx.keys = make(map[string]reflect.Value, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		idx.keys[reflectMapKeyToString(iter.Key())] = iter.Key()
+	}
+}
+
+// mapKeyIndexCache holds the key indexes of the maps that were accessed most recently.
+type mapKeyIndexCache struct {
+	mutex   sync.Mutex
+	indexes map[mapKeyIndexID]*mapKeyIndex
+	order   []mapKeyIndexID // least recently created first
+}
+
+// lookup returns the key of the map for the encoding.
+// The index of the map is rebuilt if the map has changed since it was created.
+func (c *mapKeyIndexCache) lookup(encoded string, m reflect.Value) reflect.Value {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	idx := c.index(m)
+	if idx.keys != nil {
+		if key, found := idx.keys[encoded]; found && m.MapIndex(key).IsValid() {
+			return key
+		}
+		// changed without changing its length or missing key
+		idx = c.newIndex(m)
+	}
+	idx.buildKeys(m)
+	if key, found := idx.keys[encoded]; found {
+		return key
+	}
+	return reflect.Value{}
+}
+
+// sortedKeys returns the keys of the map in sorted order, see sortMapKeys.
+// The keys are sorted again if the keys of the map have changed, see hasSortedKeysOf.
+func (c *mapKeyIndexCache) sortedKeys(m reflect.Value) []reflect.Value {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	idx := c.index(m)
+	if now := time.Now(); !idx.hasSortedKeysOf(m, now) {
+		idx.sorted = sortMapKeys(m.MapKeys())
+		idx.checked = now
+	}
+	return idx.sorted
+}
+
+// resortedKeys sorts the keys of the map again, for a change that was not found by sortedKeys.
+func (c *mapKeyIndexCache) resortedKeys(m reflect.Value) []reflect.Value {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	idx := c.index(m)
+	idx.sorted = sortMapKeys(m.MapKeys())
+	idx.checked = time.Now()
+	return idx.sorted
+}
+
+// index returns the index of the map, a new one if the length of the map has changed.
+// pre: locked
+func (c *mapKeyIndexCache) index(m reflect.Value) *mapKeyIndex {
+	if idx, ok := c.indexes[mapKeyIndexIDOf(m)]; ok && idx.length == m.Len() {
+		return idx
+	}
+	return c.newIndex(m)
+}
+
+// pre: locked
+func (c *mapKeyIndexCache) newIndex(m reflect.Value) *mapKeyIndex {
+	id := mapKeyIndexIDOf(m)
+	if _, ok := c.indexes[id]; !ok {
+		if len(c.order) == maxMapKeyIndexes {
+			delete(c.indexes, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, id)
+	}
+	idx := &mapKeyIndex{length: m.Len()}
+	c.indexes[id] = idx
+	return idx
+}
+
+// sortMapKeys sorts strings, numbers and bools by value and other keys by their encoding.
+func sortMapKeys(keys []reflect.Value) []reflect.Value {
+	if len(keys) == 0 {
+		return keys
+	}
+	switch keys[0].Kind() {
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return naturalLess(keys[i].String(), keys[j].String()) })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	case reflect.Float32, reflect.Float64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Float() < keys[j].Float() })
+	case reflect.Bool:
+		sort.Slice(keys, func(i, j int) bool { return !keys[i].Bool() && keys[j].Bool() })
+	default:
+		encoded := make([]string, len(keys))
+		for i, each := range keys {
+			encoded[i] = reflectMapKeyToString(each)
+		}
+		sort.Sort(keysByEncoding{keys, encoded})
+	}
+	return keys
+}
+
+type keysByEncoding struct {
+	keys    []reflect.Value
+	encoded []string
+}
+
+func (k keysByEncoding) Len() int           { return len(k.keys) }
+func (k keysByEncoding) Less(i, j int) bool { return naturalLess(k.encoded[i], k.encoded[j]) }
+func (k keysByEncoding) Swap(i, j int) {
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+	k.encoded[i], k.encoded[j] = k.encoded[j], k.encoded[i]
+}
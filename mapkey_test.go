@@ -0,0 +1,153 @@
+package structexplorer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mapKeyPoint struct {
+	X, Y int
+	Name string
+}
+
+func TestMapKeyEncoding(t *testing.T) {
+	p := &mapKeyPoint{}
+	for _, each := range []struct {
+		key  any
+		want string
+	}{
+		{true, "true"},
+		{1.5, "1.5"},
+		{float32(0.1), "0.1"},
+		{int8(-3), "-3"},
+		{mapKeyPoint{X: 1, Y: 2, Name: "a]"}, `{X:1,Y:2,Name:"a]"}`},
+		{[2]int{3, 4}, "[3,4]"},
+		{[1]any{"a"}, `[string("a")]`},
+		{(*mapKeyPoint)(nil), "nil"},
+	} {
+		if got, want := reflectMapKeyToString(reflect.ValueOf(each.key)), each.want; got != want {
+			t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+		}
+	}
+	if got := reflectMapKeyToString(reflect.ValueOf(p)); got[:2] != "0x" {
+		t.Errorf("got %s want address", got)
+	}
+}
+
+func TestMapKeyAndBackForKinds(t *testing.T) {
+	p := &mapKeyPoint{}
+	for _, m := range []any{
+		map[bool]string{true: "yes", false: "no"},
+		map[float64]string{1.5: "one and a half", -2: "minus two"},
+		map[mapKeyPoint]string{{X: 1, Y: 2}: "a", {X: 2, Y: 1, Name: "[b]"}: "b"},
+		map[[2]string]string{{"a", "b"}: "ab", {"b", "a"}: "ba"},
+		map[*mapKeyPoint]string{p: "p", nil: "nil"},
+		map[any]string{1: "int", "1": "string", int64(1): "int64"},
+	} {
+		rm := reflect.ValueOf(m)
+		iter := rm.MapRange()
+		for iter.Next() {
+			ks := reflectMapKeyToString(iter.Key())
+			if got, want := (fieldAccess{owner: m, key: ks}).value(), iter.Value().Interface(); got != want {
+				t.Errorf("%T %s: got [%[3]v:%[3]T] want [%[4]v:%[4]T]", m, ks, got, want)
+			}
+		}
+	}
+}
+
+func TestMapKeyPathExpression(t *testing.T) {
+	type data struct {
+		Points map[mapKeyPoint]string
+		Grid   map[[2]int]string
+	}
+	d := data{
+		Points: map[mapKeyPoint]string{{X: 1, Y: 2, Name: "a]"}: "found"},
+		Grid:   map[[2]int]string{{3, 4}: "cell"},
+	}
+	for _, each := range []string{
+		`d.Points[{X:1,Y:2,Name:"a]"}]`,
+		`d.Grid[[3,4]]`,
+	} {
+		p, err := parsePath(each, []string{"d"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := resolveAccessPath(d, p.keys); err != nil {
+			t.Error(err)
+		}
+		if got, want := formatPath(d, "d", p.keys), each; got != want {
+			t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+		}
+	}
+}
+
+func TestMapKeyIndex(t *testing.T) {
+	m := map[mapKeyPoint]int{}
+	for i := range 1000 {
+		m[mapKeyPoint{X: i}] = i
+	}
+	rm := reflect.ValueOf(m)
+	ks := reflectMapKeyToString(reflect.ValueOf(mapKeyPoint{X: 500}))
+	if got, want := rm.MapIndex(stringToReflectMapKey(ks, rm)).Int(), int64(500); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// replace a key without changing the length
+	delete(m, mapKeyPoint{X: 999})
+	m[mapKeyPoint{X: 1000}] = 1000
+	ks = reflectMapKeyToString(reflect.ValueOf(mapKeyPoint{X: 1000}))
+	if got, want := rm.MapIndex(stringToReflectMapKey(ks, rm)).Int(), int64(1000); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	ks = reflectMapKeyToString(reflect.ValueOf(mapKeyPoint{X: 999}))
+	if got := stringToReflectMapKey(ks, rm); got.IsValid() {
+		t.Errorf("got %v want invalid", got)
+	}
+}
+
+func TestMapKeyIndexSortedKeys(t *testing.T) {
+	m := map[int]string{1: "a", 2: "b", 3: "c"}
+	rm := reflect.ValueOf(m)
+	if got, want := mapKeyIndexes.sortedKeys(rm)[0].Int(), int64(1); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// replace a key without changing the length
+	delete(m, 1)
+	m[4] = "d"
+	keys := []int64{}
+	for _, each := range mapKeyIndexes.sortedKeys(rm) {
+		keys = append(keys, each.Int())
+	}
+	if got, want := fmt.Sprint(keys), "[2 3 4]"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMapKeyIndexSampledKeys(t *testing.T) {
+	m := map[int]bool{}
+	for i := range 1000 {
+		m[i] = true
+	}
+	rm := reflect.ValueOf(m)
+	mapKeyIndexes.sortedKeys(rm)
+	// replace a key that is not sampled without changing the length
+	delete(m, 1)
+	m[5000] = true
+	if got, want := mapKeyIndexes.sortedKeys(rm)[1].Int(), int64(1); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// a page with the missing key is sorted again
+	page := newMapPage(rm, interval{from: 0, to: 50})
+	if got, want := page.sorted[1].Int(), int64(2); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// all keys are checked after the interval
+	delete(m, 2)
+	m[6000] = true
+	defer func(d time.Duration) { mapKeyCheckInterval = d }(mapKeyCheckInterval)
+	mapKeyCheckInterval = 0
+	if got, want := mapKeyIndexes.sortedKeys(rm)[1].Int(), int64(3); got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
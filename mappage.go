@@ -0,0 +1,100 @@
+package structexplorer
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// mapPageLength is the maximum number of map entries listed at once;
+// larger maps are listed as pages of keys in sorted order.
+var mapPageLength = 50
+
+// mapPage is a range of the entries of a map with its keys in sorted order.
+// Its key in an access path is an interval such as 0:50 that is followed by the encoded key of an entry.
+type mapPage struct {
+	m      reflect.Value
+	sorted []reflect.Value // all keys of the map
+	interval
+}
+
+// newMapPage returns the page of entries in the interval, the whole map if the interval is zero.
+// The keys of a page with entries are all checked to be in the map.
+func newMapPage(m reflect.Value, i interval) mapPage {
+	sorted := mapKeyIndexes.sortedKeys(m)
+	page := pageInterval(sorted, i)
+	if page.to-page.from <= mapPageLength && !hasMapKeys(m, sorted[page.from:page.to]) {
+		// changed since the sorted keys were checked
+		sorted = mapKeyIndexes.resortedKeys(m)
+		page = pageInterval(sorted, i)
+	}
+	return mapPage{m: m, sorted: sorted, interval: page}
+}
+
+// pageInterval returns the interval within the sorted keys.
+func pageInterval(sorted []reflect.Value, i interval) interval {
+	if i == zeroInterval || i.to > len(sorted) {
+		i.to = len(sorted)
+	}
+	if i.from > i.to {
+		// map has shrunk
+		i.from = i.to
+	}
+	return i
+}
+
+// String returns the first and last key of the page and its size; used by printString.
+func (p mapPage) String() string {
+	if p.size() == 0 {
+		return "(0)"
+	}
+	return fmt.Sprintf("%s … %s (%d)",
+		ellipsisKey(printString(p.sorted[p.from].Interface())),
+		ellipsisKey(printString(p.sorted[p.to-1].Interface())),
+		p.size())
+}
+
+// mapType returns the type of the map with the interval of the page.
+func (p mapPage) mapType() string {
+	return fmt.Sprintf("%s [%d:%d]", p.m.Type(), p.from, p.to)
+}
+
+// fields returns the entries of the page if it is small enough, otherwise ranges of keys.
+// Only the keys of the listed entries or the bounds of the listed ranges are printed.
+func (p mapPage) fields() []fieldAccess {
+	list := []fieldAccess{}
+	owner := p.m.Interface()
+	if p.size() <= mapPageLength {
+		elemType := p.m.Type().Elem().String()
+		for _, key := range p.sorted[p.from:p.to] {
+			list = append(list, fieldAccess{
+				Type:  elemType,
+				owner: owner,
+				label: printString(key.Interface()),
+				key:   reflectMapKeyToString(key),
+			})
+		}
+		return list
+	}
+	step := mapPageLength
+	for p.size() > step*mapPageLength {
+		step *= mapPageLength
+	}
+	mapType := p.m.Type().String()
+	for from := p.from; from < p.to; from += step {
+		to := min(from+step, p.to)
+		list = append(list, fieldAccess{
+			Type:  mapType,
+			owner: owner,
+			key:   makeIntervalKey(from, to),
+		})
+	}
+	return list
+}
+
+// ellipsisKey shortens a key so that both bounds of a range fit in the value of an entry.
+func ellipsisKey(s string) string {
+	if limit := maxFieldValueStringLength / 3; len(s) > limit {
+		return s[:limit-1] + "…"
+	}
+	return s
+}
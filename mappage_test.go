@@ -0,0 +1,109 @@
+package structexplorer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMapPages(t *testing.T) {
+	m := map[string]int{}
+	for i := range 200_000 {
+		m[fmt.Sprintf("k%d", i)] = i
+	}
+	buckets := newFields(m)
+	if got, want := len(buckets), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := buckets[1].key, "125000:200000"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// natural order of keys
+	if got, want := printString(buckets[0].value()), `"k0" … "k124999" (125000)`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	sub := newFields(valueAtAccessPath(m, []string{"125000:200000"}))
+	if got, want := len(sub), 30; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	entries := newFields(valueAtAccessPath(m, []string{"125000:200000", "127500:127550"}))
+	if got, want := len(entries), 50; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := entries[0].label, `"k127500"`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	path := []string{"125000:200000", "127500:127550", entries[0].key}
+	if got, want := valueAtAccessPath(m, path), 127500; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := formatPath(m, "m", path), `m["k127500"]`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMapPageOfShrunkMap(t *testing.T) {
+	m := map[int]bool{}
+	for i := range 100 {
+		m[i] = true
+	}
+	page := valueAtAccessPath(m, []string{"50:100"}).(mapPage)
+	if got, want := page.mapType(), "map[int]bool [50:100]"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	for i := range 60 {
+		delete(m, i)
+	}
+	page = valueAtAccessPath(m, []string{"50:100"}).(mapPage)
+	if got, want := page.size(), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestBuildMapPageWithLength(t *testing.T) {
+	type data struct{ Big map[int]string }
+	d := data{Big: map[int]string{}}
+	for i := range 120 {
+		d.Big[i] = strings.Repeat("x", i%3)
+	}
+	s := NewService("d", d).(*service)
+	if err := s.explorer.explorePath("d.Big[100:120]"); err != nil {
+		t.Fatal(err)
+	}
+	b := newIndexDataBuilder()
+	s.explorer.buildIndexData(b)
+	cell := b.data.Rows[0].Cells[1]
+	if got, want := cell.Type, "map[int]string [100:120]"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := cell.Length, 120; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// zeros are hidden
+	if got, want := cell.Fields[2].Key, "103"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestMapPageAfterReplacedKey(t *testing.T) {
+	m := map[int]bool{}
+	for i := range 100 {
+		m[i] = true
+	}
+	page := valueAtAccessPath(m, []string{"50:100"}).(mapPage)
+	if got, want := page.String(), "50 … 99 (50)"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// replace a key without changing the length
+	delete(m, 50)
+	m[200] = true
+	page = valueAtAccessPath(m, []string{"50:100"}).(mapPage)
+	if got, want := page.String(), "51 … 200 (50)"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	for _, each := range page.fields() {
+		if each.label == "50" {
+			t.Error("deleted key listed")
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package structexplorer
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// DumpOptions limits the values that are included in an offline dump, see Service.DumpOffline.
+type DumpOptions struct {
+	// Maximum number of fields between an explored value and an included value.
+	// Uses 10 as default.
+	MaxDepth int
+	// Maximum number of included values (structs, maps, slices and arrays).
+	// Uses 10000 as default.
+	MaxValues int
+}
+
+func (o DumpOptions) maxDepth() int {
+	if o.MaxDepth == 0 {
+		return 10
+	}
+	return o.MaxDepth
+}
+
+func (o DumpOptions) maxValues() int {
+	if o.MaxValues == 0 {
+		return 10000
+	}
+	return o.MaxValues
+}
+
+//go:embed offline_tmpl.html
+var offlineHTML string
+
+//go:embed offline.js
+var offlineJS string
+
+var offlineTemplate = template.Must(template.New("offline").Parse(offlineHTML))
+
+type (
+	offlinePageData struct {
+		Script template.JS
+		Style  template.CSS
+		Dump   offlineDump
+	}
+	// offlineDump is the graph of values reachable from the explored values.
+	// It is embedded as JSON in the page and rendered by offline.js.
+	offlineDump struct {
+		CreatedAt string        `json:"createdAt"`
+		Cells     []offlineCell `json:"cells"`
+		Nodes     []offlineNode `json:"nodes"`
+		// true if values were left out because of the depth or size limit
+		Truncated bool `json:"truncated,omitempty"`
+	}
+	offlineCell struct {
+		Row       int  `json:"row"`
+		Column    int  `json:"column"`
+		Node      int  `json:"node"`
+		IsRoot    bool `json:"isRoot,omitempty"`
+		HideZeros bool `json:"hideZeros,omitempty"`
+	}
+	offlineNode struct {
+		Label  string         `json:"label"`
+		Path   string         `json:"path"`
+		Type   string         `json:"type"`
+		Length int            `json:"length,omitempty"`
+		Fields []offlineField `json:"fields"`
+	}
+	offlineField struct {
+		Label string `json:"label"`
+		Type  string `json:"type"`
+		Value string `json:"value"`
+		Zero  bool   `json:"zero,omitempty"`
+		// index in Nodes plus one; 0 if the value cannot be explored or is not included
+		Node int `json:"node,omitempty"`
+		// true if the value can be explored but is not included
+		Cut bool `json:"cut,omitempty"`
+	}
+)
+
+// DumpOffline writes an HTML file with the values reachable from the explored values,
+// up to the depth and size limits, that can be explored in the Browser without a running process.
+func (s *service) DumpOffline(filename string, opts ...DumpOptions) error {
+	if filename == "" {
+		filename = "structexplorer.html"
+	}
+	return s.dumpFile(filename, FormatOfflineHTML, opts...)
+}
+
+func writeOfflineHTML(w io.Writer, dump offlineDump) error {
+	return offlineTemplate.Execute(w, offlinePageData{
+		Script: template.JS(offlineJS),
+		Style:  template.CSS(styleCSS),
+		Dump:   dump,
+	})
+}
+
+// offlineDump walks all values reachable from the explored values, breadth first per cell.
+// pre: protected
+func (e *explorer) offlineDump(options DumpOptions, now time.Time) offlineDump {
+	w := &offlineWalker{
+		dump:      offlineDump{CreatedAt: now.Format(time.DateTime), Cells: []offlineCell{}, Nodes: []offlineNode{}},
+		maxDepth:  options.maxDepth(),
+		maxValues: options.maxValues(),
+		seen:      map[offlineIdentity]int{},
+	}
+	for _, each := range e.snapshot() {
+		w.dump.Cells = append(w.dump.Cells, offlineCell{
+			Row:       each.row,
+			Column:    each.column,
+			Node:      w.walkCell(each.access),
+			IsRoot:    each.access.isRoot,
+			HideZeros: each.access.hideZeros,
+		})
+	}
+	return w.dump
+}
+
+type offlineWalker struct {
+	dump      offlineDump
+	maxDepth  int
+	maxValues int
+	seen      map[offlineIdentity]int // node of values that are referenced
+	queue     []offlineItem
+}
+
+// offlineItem is a value of a node of which the fields are not yet walked.
+type offlineItem struct {
+	node  int // index in Nodes
+	value any
+	depth int
+	// path to which the keys of the fields are appended
+	base string
+	// added to the indices of the elements of a range of a slice or array
+	offset int
+}
+
+// offlineIdentity is used to include values that are referenced more than once only once, also for cycles.
+type offlineIdentity struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+func offlineIdentityOf(v any) (offlineIdentity, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map:
+		return offlineIdentity{typ: rv.Type(), ptr: rv.Pointer()}, true
+	case reflect.Slice:
+		return offlineIdentity{typ: rv.Type(), ptr: rv.Pointer(), len: rv.Len()}, true
+	}
+	return offlineIdentity{}, false
+}
+
+// walkCell adds the value of the cell and all values reachable from it, while holding its lock.
+// It returns the node of the value, plus one.
+func (w *offlineWalker) walkCell(access objectAccess) int {
+	defer access.lock()()
+	value := access.Value()
+	if id, ok := offlineIdentityOf(value); ok {
+		if node, ok := w.seen[id]; ok {
+			return node
+		}
+	}
+	path := access.explorePath()
+	if path == "" {
+		path = access.label
+	}
+	item := offlineItem{value: value, base: path}
+	if _, isPage := value.(mapPage); !isPage && access.sliceRange.size() > 1 {
+		item.offset = access.sliceRange.from
+	}
+	node := w.add(access.label, path, access.typeName, item)
+	w.walk()
+	return node
+}
+
+// add appends a node for the value of the item and queues the item to walk its fields.
+// It returns the node of the value, plus one.
+func (w *offlineWalker) add(label, path, typeName string, item offlineItem) int {
+	if typeName == "" {
+		typeName = fmt.Sprintf("%T", item.value)
+	}
+	if p, ok := item.value.(mapPage); ok {
+		typeName = p.mapType()
+	}
+	item.node = len(w.dump.Nodes)
+	w.dump.Nodes = append(w.dump.Nodes, offlineNode{
+		Label:  label,
+		Path:   path,
+		Type:   typeName,
+		Length: lengthOf(item.value),
+		Fields: []offlineField{},
+	})
+	if id, ok := offlineIdentityOf(item.value); ok {
+		w.seen[id] = item.node + 1
+	}
+	w.queue = append(w.queue, item)
+	return item.node + 1
+}
+
+func (w *offlineWalker) walk() {
+	for len(w.queue) > 0 {
+		item := w.queue[0]
+		w.queue = w.queue[1:]
+		fields := []offlineField{}
+		for _, each := range newFields(item.value) {
+			valString := safeComputeValueString(each)
+			label, key := each.displayKey(), each.key
+			if item.offset > 0 {
+				ik, _ := strconv.Atoi(key)
+				key = strconv.Itoa(ik + item.offset)
+				label = key
+			}
+			field := offlineField{Label: label, Type: each.Type, Value: valString, Zero: isZeroPrintstring(valString)}
+			if value, ok := safeFieldValue(each); ok && value != nil && canExplore(value) {
+				field.Node, field.Cut = w.child(item, each, key, label, value)
+			}
+			fields = append(fields, field)
+		}
+		w.dump.Nodes[item.node].Fields = fields
+	}
+}
+
+// child returns the node of the value of a field, plus one, and whether it was left out.
+func (w *offlineWalker) child(parent offlineItem, field fieldAccess, key, label string, value any) (int, bool) {
+	if id, ok := offlineIdentityOf(value); ok {
+		if node, ok := w.seen[id]; ok {
+			return node, false
+		}
+	}
+	if parent.depth >= w.maxDepth || len(w.dump.Nodes) >= w.maxValues {
+		w.dump.Truncated = true
+		return 0, true
+	}
+	path := parent.base + formatPathKey(field.owner, key)
+	item := offlineItem{value: value, depth: parent.depth + 1, base: path}
+	if isIntervalKey(key) {
+		// a range is not part of the path of its elements, see formatPath
+		item.base = parent.base
+		if k := kindOf(field.owner); k == reflect.Slice || k == reflect.Array {
+			item.offset = parseInterval(key).from
+		}
+	}
+	return w.add(label, path, "", item), false
+}
+
+// safeFieldValue returns the value of the field, or false if getting it panics.
+func safeFieldValue(fa fieldAccess) (v any, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			ok = false
+		}
+	}()
+	return fa.value(), true
+}
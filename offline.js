@@ -0,0 +1,154 @@
+// Renders an offline dump; dump is set by the page, see offline.go.
+// Cells are placed in a grid of rows and columns, like the live page.
+let cells = [];
+
+function renderOffline() {
+    cells = dump.cells.map((c) => Object.assign({}, c));
+    document.getElementById("created").textContent = dump.createdAt;
+    if (dump.truncated) {
+        document.getElementById("truncated").textContent = ", not all values are included";
+    }
+    renderGrid();
+}
+
+function cellAt(row, column) {
+    return cells.find((c) => c.row == row && c.column == column);
+}
+
+// put a cell with the node at the location or, if taken, the next free column on that row.
+function placeCell(row, column, node) {
+    while (cellAt(row, column) != null) {
+        column++;
+    }
+    cells.push({ row: row, column: column, node: node });
+}
+
+// action is one of "down", "right", "up", "toggleZeros" or "remove"
+function exploreOffline(row, column, action) {
+    const cell = cellAt(row, column);
+    const select = document.getElementById("id" + row + "-" + column);
+    switch (action) {
+        case "toggleZeros":
+            cell.hideZeros = !cell.hideZeros;
+            break;
+        case "remove":
+            cells = cells.filter((c) => c != cell);
+            break;
+        default:
+            let toRow = row;
+            let toColumn = column;
+            if (action == "down") toRow++;
+            if (action == "right") toColumn++;
+            if (action == "up") toRow = Math.max(0, toRow - 1);
+            for (const node of getSelectValues(select)) {
+                if (node != "0") {
+                    placeCell(toRow, toColumn, Number(node));
+                }
+            }
+    }
+    renderGrid();
+}
+
+function renderGrid() {
+    const grid = document.getElementById("grid");
+    grid.replaceChildren();
+    const rows = Math.max(0, ...cells.map((c) => c.row + 1));
+    for (let row = 0; row < rows; row++) {
+        const tr = grid.insertRow();
+        const columns = Math.max(0, ...cells.filter((c) => c.row == row).map((c) => c.column + 1));
+        for (let column = 0; column < columns; column++) {
+            const td = tr.insertCell();
+            const cell = cellAt(row, column);
+            if (cell != null) {
+                td.appendChild(renderCell(cell));
+            }
+        }
+    }
+}
+
+function renderCell(cell) {
+    const node = dump.nodes[cell.node - 1];
+    const col = element("div", "col");
+    const path = element("div", "path", node.label);
+    path.title = node.path;
+    col.appendChild(path);
+    col.appendChild(element("div", "typename", node.type + (node.length ? " (" + node.length + ")" : "")));
+    const select = element("select");
+    select.id = "id" + cell.row + "-" + cell.column;
+    select.multiple = true;
+    const fields = node.fields.filter((f) => !(cell.hideZeros && f.zero));
+    const width = Math.max(0, ...fields.map((f) => f.label.length));
+    for (const field of fields) {
+        const option = element("option", "", field.label.padEnd(width, " ") + ": " + field.value);
+        option.value = String(field.node || 0);
+        option.title = field.label + " : " + field.type + (field.cut ? " (not included in this dump)" : "");
+        select.appendChild(option);
+    }
+    select.size = Math.max(1, fields.length);
+    col.appendChild(select);
+    const bar = element("div", "buttonbar");
+    bar.appendChild(button("⇊", "explore all selected in the row below", () => exploreOffline(cell.row, cell.column, "down")));
+    bar.appendChild(button("⇉", "explore all selected in columns on the right", () => exploreOffline(cell.row, cell.column, "right")));
+    bar.appendChild(button("⇈", "explore all selected in the row above", () => exploreOffline(cell.row, cell.column, "up")));
+    if (node.fields.some((f) => f.zero)) {
+        bar.appendChild(button("z", "hide or show fields with zero values", () => exploreOffline(cell.row, cell.column, "toggleZeros")));
+    }
+    if (!cell.isRoot) {
+        bar.appendChild(button("x", "remove the object from this page", () => exploreOffline(cell.row, cell.column, "remove")));
+    }
+    col.appendChild(bar);
+    return col;
+}
+
+// list the fields of all included values with a key or value that contains the text.
+function searchOffline(text) {
+    const matches = document.getElementById("matches");
+    matches.replaceChildren();
+    text = text.toLowerCase();
+    if (text == "") return;
+    let count = 0;
+    dump.nodes.forEach((node, index) => {
+        for (const field of node.fields) {
+            if (count == 100) return;
+            if (!field.label.toLowerCase().includes(text) && !field.value.toLowerCase().includes(text)) continue;
+            count++;
+            const tr = matches.insertRow();
+            const link = element("a", "", node.path);
+            link.href = "javascript:void(0)";
+            link.title = "explore " + node.path + " in a new row";
+            link.onclick = () => {
+                placeCell(Math.max(0, ...cells.map((c) => c.row + 1)), 0, index + 1);
+                renderGrid();
+            };
+            tr.insertCell().appendChild(link);
+            tr.insertCell().textContent = field.label + ": " + field.value;
+        }
+    });
+}
+
+function element(tag, className, text) {
+    const node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text != null) node.textContent = text;
+    return node;
+}
+
+function button(text, title, onclick) {
+    const node = element("button", "btn", text);
+    node.title = title;
+    node.onclick = onclick;
+    return node;
+}
+
+// Return an array of the selected option values in the control.
+// Select is an HTML select element.
+function getSelectValues(select) {
+    const result = [];
+    for (const option of select.options) {
+        if (option.selected) result.push(option.value);
+    }
+    if (result.length == 0 && select.options.length == 1) {
+        result.push(select.options[0].value);
+    }
+    return result;
+}
@@ -0,0 +1,109 @@
+package structexplorer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type offlineTeam struct {
+	Name    string
+	Lead    *offlinePerson
+	Members []*offlinePerson
+	Scores  []int
+}
+
+type offlinePerson struct {
+	Name string
+	Team *offlineTeam
+}
+
+func TestOfflineDumpCycle(t *testing.T) {
+	team := &offlineTeam{Name: "a"}
+	team.Lead = &offlinePerson{Name: "alice", Team: team}
+	team.Members = []*offlinePerson{team.Lead}
+	s := NewService("team", team).(*service)
+
+	d := s.explorer.offlineDump(DumpOptions{}, time.Now())
+	if got, want := len(d.Cells), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// team, lead, members
+	if got, want := len(d.Nodes), 3; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	lead := d.Nodes[d.Nodes[0].Fields[1].Node-1]
+	if got, want := lead.Path, "team.Lead"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// back to the team
+	if got, want := lead.Fields[1].Node, 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// same lead
+	members := d.Nodes[d.Nodes[0].Fields[2].Node-1]
+	if got, want := members.Fields[0].Node, d.Nodes[0].Fields[1].Node; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestOfflineDumpLimits(t *testing.T) {
+	team := &offlineTeam{Lead: &offlinePerson{Team: &offlineTeam{}}}
+	s := NewService("team", team).(*service)
+	d := s.explorer.offlineDump(DumpOptions{MaxDepth: 1}, time.Now())
+	if !d.Truncated {
+		t.Error("truncated expected")
+	}
+	if !d.Nodes[0].Fields[0].Zero {
+		t.Error("empty name must be zero")
+	}
+	lead := d.Nodes[d.Nodes[0].Fields[1].Node-1]
+	if field := lead.Fields[1]; field.Node != 0 || !field.Cut {
+		t.Errorf("got [%v] want cut field", field)
+	}
+
+	d = s.explorer.offlineDump(DumpOptions{MaxValues: 1}, time.Now())
+	if got, want := len(d.Nodes), 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestOfflineDumpRangeOfSlice(t *testing.T) {
+	team := &offlineTeam{Scores: make([]int, 120)}
+	team.Scores[60] = 42
+	s := NewService("team", team).(*service)
+	d := s.explorer.offlineDump(DumpOptions{}, time.Now())
+	scores := d.Nodes[d.Nodes[0].Fields[3].Node-1]
+	if got, want := scores.Fields[1].Label, "50:100"; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	page := d.Nodes[scores.Fields[1].Node-1]
+	if got, want := page.Path, "team.Scores[50:100]"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := page.Fields[10].Label+"="+page.Fields[10].Value, "60=42"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestDumpOffline(t *testing.T) {
+	team := &offlineTeam{Name: "</script>"}
+	s := NewService("team", team)
+	name := filepath.Join(t.TempDir(), "offline.html")
+	if err := s.DumpOffline(name); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(name)
+	html := string(data)
+	if !strings.Contains(html, `"path":"team"`) {
+		t.Error("embedded dump expected")
+	}
+	if strings.Count(html, "</script>") != 1 {
+		t.Error("value must be escaped")
+	}
+	if err := s.DumpOffline(filepath.Join(name, "missing", "offline.html")); err == nil {
+		t.Error("error expected")
+	}
+}
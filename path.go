@@ -0,0 +1,308 @@
+package structexplorer
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// parsedPath is the result of parsing a path expression such as `users["alice@example.com"].roles[2]`.
+type parsedPath struct {
+	root string   // label of the root value
+	keys []string // access path keys, see valueAtAccessPath
+}
+
+// parsePath parses a path expression that starts with one of the root labels.
+//
+//	label.Field       field of a struct
+//	label[2]          element of a slice, array or map with integer keys
+//	label[10:20]      range of elements of a slice or array
+//	label["key"]      value of a map with string keys
+//	label[{X:1,Y:2}]  value of a map with other keys, see reflectMapKeyToString
+//	label.(*T)        type assertion, value must be of type *T (or *pkg.T) or implement the interface T
+//	*label.Pointer    the value the pointer points to
+//
+// Pointers and interfaces are dereferenced by each key, so a leading dereference operator does not change the value.
+// For compatibility, indices and ranges can also be written as dotted keys, e.g. label.Items.2
+func parsePath(expr string, rootLabels []string) (parsedPath, error) {
+	p := parsedPath{}
+	rest := strings.TrimLeft(expr, "*")
+	// longest matching root label
+	for _, each := range rootLabels {
+		if len(each) <= len(p.root) || !strings.HasPrefix(rest, each) {
+			continue
+		}
+		if after := rest[len(each):]; after == "" || after[0] == '.' || after[0] == '[' {
+			p.root = each
+		}
+	}
+	if p.root == "" {
+		return p, fmt.Errorf("path %q does not start with one of %v", expr, rootLabels)
+	}
+	rest = rest[len(p.root):]
+	for rest != "" {
+		offset := len(expr) - len(rest)
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, "(") {
+				end := strings.Index(rest, ")")
+				if end < 2 {
+					return p, fmt.Errorf("invalid type assertion at position %d in %q", offset, expr)
+				}
+				p.keys = append(p.keys, rest[:end+1])
+				rest = rest[end+1:]
+				continue
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return p, fmt.Errorf("missing field name at position %d in %q", offset+1, expr)
+			}
+			p.keys = append(p.keys, rest[:end])
+			rest = rest[end:]
+		case '[':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, `"`) {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					return p, fmt.Errorf("invalid quoted key at position %d in %q", offset+1, expr)
+				}
+				rest = rest[len(quoted):]
+				if !strings.HasPrefix(rest, "]") {
+					return p, fmt.Errorf("missing ']' at position %d in %q", len(expr)-len(rest), expr)
+				}
+				rest = rest[1:]
+				key, _ := strconv.Unquote(quoted)
+				p.keys = append(p.keys, encodeStringMapKey(key))
+				continue
+			}
+			end := closingBracket(rest)
+			if end == -1 {
+				return p, fmt.Errorf("missing ']' for '[' at position %d in %q", offset, expr)
+			}
+			key := strings.TrimSpace(rest[:end])
+			if key == "" {
+				return p, fmt.Errorf("missing index at position %d in %q", offset+1, expr)
+			}
+			p.keys = append(p.keys, key)
+			rest = rest[end+1:]
+		default:
+			return p, fmt.Errorf("unexpected %q at position %d in %q, expected '.' or '['", rest[0], offset, expr)
+		}
+	}
+	return p, nil
+}
+
+// closingBracket returns the index of the ']' that closes the key at the start of s.
+// Brackets of array keys and brackets in quoted strings are skipped.
+// It returns -1 if there is no such bracket.
+func closingBracket(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted, err := strconv.QuotedPrefix(s[i:])
+			if err != nil {
+				return -1
+			}
+			i += len(quoted) - 1
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// formatPath returns the path expression for the access path keys starting at a root value.
+// The result can be parsed by parsePath.
+func formatPath(root any, rootLabel string, keys []string) string {
+	b := new(strings.Builder)
+	b.WriteString(rootLabel)
+	current := root
+	for i, key := range keys {
+		if key == "" {
+			continue
+		}
+		// a range that is followed by an index is not part of the path, see valueAtAccessPath
+		if isIntervalKey(key) && i < len(keys)-1 {
+			continue
+		}
+		b.WriteString(formatPathKey(current, key))
+		current = fieldAccess{owner: current, key: key}.value()
+	}
+	return b.String()
+}
+
+// formatPathKey returns the part of a path expression that accesses the key of the owner.
+func formatPathKey(owner any, key string) string {
+	if isTypeAssertionKey(key) {
+		return "." + key
+	}
+	switch kindOf(owner) {
+	case reflect.Slice, reflect.Array:
+		return "[" + key + "]"
+	case reflect.Map:
+		if !isQuotedKey(key) && !isIntervalKey(key) && mapKeyKindOf(owner) == reflect.String {
+			return "[" + strconv.Quote(key) + "]"
+		}
+		return "[" + key + "]"
+	default:
+		if _, err := strconv.Atoi(key); err == nil || isIntervalKey(key) {
+			return "[" + key + "]"
+		}
+		return "." + key
+	}
+}
+
+// resolveAccessPath is like valueAtAccessPath but returns an error that explains why a key cannot be accessed.
+func resolveAccessPath(value any, path []string) (any, error) {
+	for i, key := range path {
+		if key == "" {
+			continue
+		}
+		if value == nil {
+			return nil, fmt.Errorf("cannot access %s of nil", key)
+		}
+		// check for range
+		if isIntervalKey(key) && i < len(path)-1 {
+			continue
+		}
+		if err := checkAccessKey(value, key); err != nil {
+			return nil, err
+		}
+		value = fieldAccess{owner: value, key: key}.value()
+	}
+	return value, nil
+}
+
+func checkAccessKey(owner any, key string) error {
+	if isTypeAssertionKey(key) {
+		if name := key[1 : len(key)-1]; !assertsType(owner, name) {
+			return fmt.Errorf("value is of type %T, not %s", owner, name)
+		}
+		return nil
+	}
+	rv := reflect.ValueOf(owner)
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("cannot access %s of nil %T", key, owner)
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		field, ok := rv.Type().FieldByName(key)
+		if !ok {
+			return fmt.Errorf("type %s has no field %s", rv.Type(), key)
+		}
+		registerInterfaceType(field.Type)
+	case reflect.Slice, reflect.Array:
+		if isIntervalKey(key) {
+			return nil
+		}
+		registerInterfaceType(rv.Type().Elem())
+		i, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("invalid index %s for %s", key, rv.Type())
+		}
+		if i < 0 || i >= rv.Len() {
+			return fmt.Errorf("index %d out of range [0:%d]", i, rv.Len())
+		}
+	case reflect.Map:
+		if isIntervalKey(key) {
+			return nil
+		}
+		registerInterfaceType(rv.Type().Elem())
+		mk := reflectMapKeyFor(key, rv)
+		if !mk.IsValid() || !rv.MapIndex(mk).IsValid() {
+			return fmt.Errorf("key %s not found in %s", key, rv.Type())
+		}
+	default:
+		return fmt.Errorf("cannot access %s of %s", key, rv.Type())
+	}
+	return nil
+}
+
+// isTypeAssertionKey returns true for keys such as (*pkg.Type)
+func isTypeAssertionKey(k string) bool {
+	return len(k) > 2 && k[0] == '(' && k[len(k)-1] == ')'
+}
+
+// kindOf returns the kind of the value after dereferencing pointers and interfaces.
+func kindOf(v any) reflect.Kind {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Invalid
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind()
+}
+
+// mapKeyKindOf returns the kind of the keys of a map value.
+func mapKeyKindOf(v any) reflect.Kind {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	return rv.Type().Key().Kind()
+}
+
+// interfaceTypes are the interface types, by qualified and unqualified name, that a value can be asserted to.
+// Besides error and fmt.Stringer, these are the declared interface types of the fields, elements and map values
+// accessed by the path expressions resolved so far.
+var interfaceTypes = struct {
+	mutex  sync.Mutex
+	byName map[string]reflect.Type
+}{byName: map[string]reflect.Type{}}
+
+func init() {
+	registerInterfaceType(reflect.TypeOf((*error)(nil)).Elem())
+	registerInterfaceType(reflect.TypeOf((*fmt.Stringer)(nil)).Elem())
+}
+
+func registerInterfaceType(t reflect.Type) {
+	if t.Kind() != reflect.Interface || t.Name() == "" {
+		return
+	}
+	interfaceTypes.mutex.Lock()
+	defer interfaceTypes.mutex.Unlock()
+	interfaceTypes.byName[t.String()] = t
+	interfaceTypes.byName[t.Name()] = t
+}
+
+// assertsType returns true if the value is of the type with the name, such as *pkg.Concrete or *Concrete,
+// or implements the interface type with that name.
+func assertsType(v any, name string) bool {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return false
+	}
+	if t.String() == name || unqualifiedTypeName(t.String()) == name {
+		return true
+	}
+	interfaceTypes.mutex.Lock()
+	it, ok := interfaceTypes.byName[name]
+	interfaceTypes.mutex.Unlock()
+	return ok && t.Implements(it)
+}
+
+// packageQualifier matches the package names in the name of a type, e.g. "pkg." in "map[string]*pkg.Concrete".
+var packageQualifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*\.`)
+
+// unqualifiedTypeName returns the name of a type without package names, e.g. *Concrete for *pkg.Concrete.
+func unqualifiedTypeName(name string) string {
+	return packageQualifier.ReplaceAllString(name, "")
+}
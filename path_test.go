@@ -0,0 +1,220 @@
+package structexplorer
+
+import (
+	"strings"
+	"testing"
+)
+
+type pathUser struct {
+	Name  string
+	Roles []string
+	Any   any
+	Ptr   *pathUser
+}
+
+type pathData struct {
+	Users map[string]*pathUser
+	Items []int
+	Codes map[int]string
+}
+
+func newPathData() *pathData {
+	alice := &pathUser{Name: "alice", Roles: []string{"a", "b", "admin"}}
+	alice.Any = alice
+	alice.Ptr = alice
+	return &pathData{
+		Users: map[string]*pathUser{"alice@example.com": alice, "bob": {Name: "bob"}},
+		Items: make([]int, 100),
+		Codes: map[int]string{42: "answer"},
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		expr string
+		root string
+		keys string
+	}{
+		{`data`, "data", ""},
+		{`data.Items`, "data", "Items"},
+		{`data.Items[2]`, "data", "Items|2"},
+		{`data.Items.2`, "data", "Items|2"},
+		{`data.Items[10:20]`, "data", "Items|10:20"},
+		{`data.Users["alice@example.com"].Roles[2]`, "data", `Users|"alice@example.com"|Roles|2`},
+		{`data.Users["bob"]`, "data", "Users|bob"},
+		{`data.Users["bob"].Any.(*structexplorer.pathUser)`, "data", "Users|bob|Any|(*structexplorer.pathUser)"},
+		{`my data.Items`, "my data", "Items"},
+		{`data.Users["bob"].Ptr`, "data", "Users|bob|Ptr"},
+		{`*data.Users["bob"].Ptr`, "data", "Users|bob|Ptr"},
+	}
+	for _, each := range cases {
+		t.Run(each.expr, func(t *testing.T) {
+			p, err := parsePath(each.expr, []string{"data", "my data"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := p.root, each.root; got != want {
+				t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+			}
+			if got, want := strings.Join(p.keys, "|"), each.keys; got != want {
+				t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+			}
+		})
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, each := range []string{
+		`other.Items`,
+		`data.`,
+		`data.Items[`,
+		`data.Items[]`,
+		`data.Users["bob`,
+		`data.Users["bob"`,
+		`data.()`,
+		`data-Items`,
+		`*other.Items`,
+	} {
+		t.Run(each, func(t *testing.T) {
+			if _, err := parsePath(each, []string{"data"}); err == nil {
+				t.Error("error expected")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
+
+func TestFormatPathRoundTrip(t *testing.T) {
+	data := newPathData()
+	for _, each := range []string{
+		`data.Items[2]`,
+		`data.Items[10:20]`,
+		`data.Users["alice@example.com"].Roles[2]`,
+		`data.Users["bob"].Name`,
+		`data.Users["alice@example.com"].Any.(*structexplorer.pathUser).Name`,
+		`data.Codes[42]`,
+	} {
+		t.Run(each, func(t *testing.T) {
+			p, err := parsePath(each, []string{"data"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := formatPath(data, "data", p.keys), each; got != want {
+				t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatPathFromUI(t *testing.T) {
+	data := newPathData()
+	// range followed by index as created by the page
+	if got, want := formatPath(data, "data", []string{"", "Items", "50:100", "53"}), "data.Items[53]"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestResolveAccessPath(t *testing.T) {
+	data := newPathData()
+	v, err := resolveAccessPath(data, []string{"Users", `"alice@example.com"`, "Roles", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := v, "admin"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	for _, each := range []struct {
+		path []string
+		err  string
+	}{
+		{[]string{"Missing"}, "type structexplorer.pathData has no field Missing"},
+		{[]string{"Items", "100"}, "index 100 out of range [0:100]"},
+		{[]string{"Items", "x"}, "invalid index x for []int"},
+		{[]string{"Users", "carol"}, "key carol not found in map[string]*structexplorer.pathUser"},
+		{[]string{"Users", "bob", "Ptr", "Name"}, "cannot access Name of nil *structexplorer.pathUser"},
+		{[]string{"Users", "bob", "Name", "x"}, "cannot access x of string"},
+		{[]string{"Users", "bob", "(string)"}, "value is of type *structexplorer.pathUser, not string"},
+	} {
+		_, err := resolveAccessPath(data, each.path)
+		if err == nil {
+			t.Errorf("error expected for %v", each.path)
+			continue
+		}
+		if got, want := err.Error(), each.err; got != want {
+			t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+		}
+	}
+}
+
+func TestExplorePathExpression(t *testing.T) {
+	s := NewService("data", newPathData()).(*service)
+	s.ExplorePath(`data.Users["alice@example.com"].Roles`)
+	oa := s.explorer.objectAt(0, 1)
+	if got, want := oa.label, `data.Users["alice@example.com"].Roles`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := oa.Value().([]string)[2], "admin"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if err := s.explorer.explorePath(`data.Users["carol"]`); err == nil {
+		t.Error("error expected")
+	}
+	if err := s.explorer.explorePath(`*data.Users["bob"]`); err != nil {
+		t.Error(err)
+	}
+	if err := s.explorer.explorePath(`data.Users["bob"].Name`); err != nil {
+		t.Error(err)
+	}
+}
+
+type pathShape interface {
+	Area() float64
+}
+
+type pathSquare struct {
+	Side float64
+}
+
+func (s *pathSquare) Area() float64 { return s.Side * s.Side }
+
+type pathDrawing struct {
+	Shape pathShape
+	Any   any
+}
+
+func TestResolveTypeAssertion(t *testing.T) {
+	drawing := &pathDrawing{Shape: &pathSquare{Side: 2}, Any: &pathSquare{Side: 3}}
+	for _, each := range [][]string{
+		{"Any", "(*structexplorer.pathSquare)", "Side"},
+		{"Any", "(*pathSquare)", "Side"},
+		{"Shape", "(structexplorer.pathShape)"},
+		// known by the declared type of Shape
+		{"Any", "(pathShape)"},
+	} {
+		if _, err := resolveAccessPath(drawing, each); err != nil {
+			t.Errorf("%v: %v", each, err)
+		}
+	}
+	v, _ := resolveAccessPath(drawing, []string{"Any", "(*pathSquare)", "Side"})
+	if got, want := v, 3.0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	for _, each := range []struct {
+		path []string
+		err  string
+	}{
+		{[]string{"Any", "(*pathUser)"}, "value is of type *structexplorer.pathSquare, not *pathUser"},
+		{[]string{"Any", "(fmt.Stringer)"}, "value is of type *structexplorer.pathSquare, not fmt.Stringer"},
+		{[]string{"Any", "(pathSquare)"}, "value is of type *structexplorer.pathSquare, not pathSquare"},
+	} {
+		_, err := resolveAccessPath(drawing, each.path)
+		if err == nil {
+			t.Errorf("error expected for %v", each.path)
+			continue
+		}
+		if got, want := err.Error(), each.err; got != want {
+			t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+		}
+	}
+}
@@ -1,3 +1,6 @@
+// set when leaving the page on purpose so that a break is not resumed.
+let navigating = false;
+
 function explore(row, column, selectNode, action) {
     if (selectNode == null) {
         console.log(row, column, "selectNode is null");
@@ -13,16 +16,276 @@ function explore(row, column, selectNode, action) {
         action: action,
         selections: getSelectValues(selectNode)
     }));
-    xhr.onload = function() { window.location.reload(); }
+    xhr.onload = function() {
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// loadCell replaces the placeholder of a cell that was left out of the page to keep it responsive
+function loadCell(row, column) {
+    const url = new URL(window.location.href);
+    url.searchParams.set("cell", row + "," + column);
+    const xhr = new XMLHttpRequest();
+    xhr.open("GET", url);
+    xhr.onload = function() {
+        const node = document.getElementById("cell-" + row + "-" + column);
+        if (node == null) {
+            return;
+        }
+        if (xhr.status != 200) {
+            node.querySelector(".loading").textContent = xhr.responseText;
+            return;
+        }
+        node.outerHTML = xhr.responseText;
+        // scripts in the loaded cell are not executed, update visible size of select
+        const select = document.getElementById("id" + row + "-" + column);
+        if (select != null) {
+            select.setAttribute("size", select.options.length);
+        }
+    }
+    xhr.send();
+}
+
+// action is either "sort" or "filter"
+function setCellOption(row, column, action, value) {
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        row: row,
+        column: column,
+        action: action,
+        selections: [value]
+    }));
+    xhr.onload = function() {
+        if (xhr.status != 200) {
+            alert(xhr.responseText);
+            return;
+        }
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// action is either "resume" or "resumeAll"
+function resume(action) {
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: action
+    }));
+    xhr.onload = function() {
+        // show the next paused goroutine, if any
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// explore the stack frame, with its locals, at the index of the paused goroutine.
+function exploreFrame(index) {
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: "exploreFrame",
+        selections: [String(index)]
+    }));
+    xhr.onload = function() {
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+function extendBreak() {
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: "extendBreak"
+    }));
+    xhr.onload = function() {
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// show the seconds left before the deadline (Unix milliseconds) in the element.
+function startCountdown(element, deadline) {
+    function update() {
+        const left = Math.max(0, Math.round((deadline - Date.now()) / 1000));
+        element.textContent = "resumes in " + left + "s";
+    }
+    update();
+    setInterval(update, 1000);
+}
+
+// action is either "toggleBreakSite" or "toggleBreakSites"
+function toggleBreakSite(action, location) {
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: action,
+        selections: [location]
+    }));
+}
+
+// Save the layout in the browser and restore it once after the service has restarted.
+// The key changes with each start of the service.
+function restoreLayout(key, layout, workspace) {
+    const storageKey = "structexplorer-layout:" + window.location.pathname + ":" + workspace;
+    const saved = JSON.parse(localStorage.getItem(storageKey) || "null");
+    localStorage.setItem(storageKey, JSON.stringify({ key: key, layout: layout }));
+    if (saved == null || saved.key == key || saved.layout == null) {
+        return;
+    }
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: "restoreLayout",
+        selections: [JSON.stringify(saved.layout)]
+    }));
+    xhr.onload = function() {
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// explore the value at the path expression; shows the error if the path is invalid.
+function explorePath(expression) {
+    if (expression == "") return;
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: "explorePath",
+        selections: [expression]
+    }));
+    xhr.onload = function() {
+        if (xhr.status != 200) {
+            alert(xhr.responseText);
+            return;
+        }
+        navigating = true;
+        window.location.reload();
+    }
 }
 
-function resume() {
+// action is either "watch" with a path expression or "unwatch" with the label of a watch,
+// or "trackPath" or "untrack" with a path expression
+function watch(action, value) {
+    if (value == "") return;
     const xhr = new XMLHttpRequest();
     xhr.open("POST", window.location.href);
     xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
     xhr.send(JSON.stringify({
-        action: "resume" 
+        action: action,
+        selections: [value]
     }));
+    xhr.onload = function() {
+        if (xhr.status != 200) {
+            alert(xhr.responseText);
+            return;
+        }
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// cell that was clicked first to compare with another cell
+let compareFrom = null;
+
+// compare the value of the cell with that of the cell that was clicked before.
+function compareCell(row, column, button) {
+    if (compareFrom == null) {
+        compareFrom = { row: row, column: column, button: button };
+        button.classList.add("pending");
+        return;
+    }
+    const from = compareFrom;
+    compareFrom = null;
+    from.button.classList.remove("pending");
+    if (from.row == row && from.column == column) return;
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        row: row,
+        column: column,
+        action: "compare",
+        selections: [from.row + "," + from.column]
+    }));
+    xhr.onload = function() {
+        if (xhr.status != 200) {
+            alert(xhr.responseText);
+            return;
+        }
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// action is one of "align", "unalign" or "uncompare"
+function compareAction(action, left, right) {
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: action,
+        selections: [left, right]
+    }));
+    xhr.onload = function() {
+        navigating = true;
+        window.location.reload();
+    }
+}
+
+// switch to another workspace; an empty name asks for a new one, which is created first.
+function switchWorkspace(name) {
+    if (name != "") {
+        navigating = true;
+        window.location.search = "?workspace=" + encodeURIComponent(name);
+        return;
+    }
+    name = window.prompt("name of the new workspace");
+    if (name == null || name == "") {
+        navigating = true;
+        window.location.reload();
+        return;
+    }
+    const xhr = new XMLHttpRequest();
+    xhr.open("POST", window.location.href);
+    xhr.setRequestHeader("Content-Type", "application/json; charset=UTF-8")
+    xhr.send(JSON.stringify({
+        action: "newWorkspace",
+        selections: [name]
+    }));
+    xhr.onload = function() {
+        if (xhr.status != 200) {
+            alert(xhr.responseText);
+            navigating = true;
+            window.location.reload();
+            return;
+        }
+        switchWorkspace(name);
+    }
+}
+
+// copy a link to the clipboard that explores the dotted path when opened.
+function copyLink(path) {
+    copyLayoutLink("explore=" + encodeURIComponent(path));
+}
+
+// copy a link to the clipboard with the query to explore paths.
+function copyLayoutLink(query) {
+    const link = window.location.origin + window.location.pathname + "?" + query;
+    navigator.clipboard.writeText(link).then(
+        () => console.log("copied", link),
+        () => window.prompt("copy this link", link),
+    );
 }
 
 // Return an array of the selected option values in the control.
@@ -1,16 +1,19 @@
 package structexplorer
 
 import (
-	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"log/slog"
 	"net/http"
-	"os"
+	"net/url"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Service is an HTTP Handler to explore one or more values (structures).
@@ -22,15 +25,45 @@ type Service interface {
 	// Break accepts 0 or 1 Options
 	Break(opts ...Options)
 
-	// Dump writes an HTML file for displaying the current state of the explorer and its entries.
-	Dump(optionFilename ...string)
+	// StartAgent serves the values using a JSON protocol instead of the page, accepts 0 or 1 Options.
+	// The viewer of the structexplorer command explores the values of one or more agents.
+	StartAgent(opts ...Options)
+
+	// AgentHandler returns the HTTP Handler of the JSON protocol, see StartAgent; accepts 0 or 1 Options.
+	// Changing values is only allowed with Options.AllowEdit.
+	AgentHandler(opts ...Options) http.Handler
+
+	// Dump writes a file for displaying the current state of the explorer and its entries.
+	// The format is HTML unless the extension of the filename is .json, .yaml, .yml or .md.
+	Dump(optionFilename ...string) error
+
+	// DumpTo writes the current state of the explorer and its entries in a format.
+	// DumpOptions only apply to FormatOfflineHTML.
+	DumpTo(w io.Writer, format Format, opts ...DumpOptions) error
+
+	// DumpOffline writes an HTML file with the values reachable from the explored values, up to the limits
+	// of 0 or 1 DumpOptions, that can be explored in the Browser without a running process.
+	DumpOffline(filename string, opts ...DumpOptions) error
 
 	// Explore adds or replaces (matching on label) a new entry for a value unless it cannot be explored.
 	// The object will be placed on the next available column on row 1.
+	// Values explored from a replaced entry are read from the new value.
 	Explore(label string, value any, options ...ExploreOption) Service
 
 	// ExplorePath adds a new entry for a value at the specified access path unless it cannot be explored.
 	ExplorePath(dottedPath string, options ...ExploreOption) Service
+
+	// Watch adds or replaces (matching on label) a path expression that is shown with its current value
+	// and the time it last changed, independent of the explored values.
+	Watch(label, path string) Service
+
+	// Track samples the numeric value at the path expression on an interval.
+	// Its recent history is shown as a sparkline and can be downloaded as CSV.
+	Track(path string) Service
+
+	// Compare adds a comparison of the values at two labels or path expressions that is shown
+	// as a tree of their fields, listing differing, missing and extra fields.
+	Compare(labelA, labelB string, options ...CompareOption) Service
 }
 
 //go:embed index_tmpl.html
@@ -48,53 +81,68 @@ func (s *service) init() {
 type service struct {
 	explorer      *explorer
 	indexTemplate *template.Template
-	httpServer    *http.Server
+	workspaces    map[string]*explorer // name -> explorer, other than the default
+	workspaceAge  []string             // names of the workspaces, oldest first
+	defaultOwner  string               // session of the browser that uses the default workspace
+	session       *breakSession        // set when paused using Break
+	startedAt     time.Time            // identifies this service for the layout saved in the browser
+	watches       []*watch             // shown on all workspaces
+	tracks        []*track             // sampled on an interval, shown on all workspaces
+	comparisons   []*comparison        // shown on all workspaces
+	sampling      bool                 // true if the goroutine that samples the tracks is running
+	debugMux      *http.ServeMux       // set when registered using RegisterDebug
+	// serializes changing the explored values by the agent with reading them outside the explorer lock.
+	// Must not be acquired while holding the explorer lock.
+	values sync.RWMutex
 }
 
 // NewService creates a new to explore one or more values (structures).
 func NewService(labelValuePairs ...any) Service {
-	s := &service{explorer: newExplorerOnAll(labelValuePairs...)}
+	s := &service{
+		explorer:   newExplorerOnAll(labelValuePairs...),
+		workspaces: map[string]*explorer{},
+		startedAt:  time.Now(),
+	}
 	s.init()
 	return s
 }
 
 // Break will listen and serve on the default endpoint and opens a window.
-// The explorer page will have a button "Resume" that stops the server
-// and unblocks the go-routine that started it.
+// All goroutines that call Break share the same server; its page lists each paused goroutine.
+// The explorer page will have a button "Resume" that unblocks the go-routine that started it.
+// The server stops when no more goroutines are paused.
+// Breakpoints can be disabled per call site from the explorer page
+// or all at once by setting the environment variable STRUCTEXPLORER_BREAK=off.
 func Break(keyvaluePairs ...any) {
-	NewService(keyvaluePairs...).Break(Options{
-		ServeMux: new(http.ServeMux),
-	})
+	location := callerLocation()
+	if !sites.hit(location, func(int) bool { return true }) {
+		return
+	}
+	breaks.pause(NewService(keyvaluePairs...).(*service), location, nil)
 }
 
-// Break will listen and serve on the given http port and path.
-// it accepts 0 or 1 Options to override defaults.
-// The opened explorer page will have a button "Resume" that stops the server
-// and unblocks the go-routine that started it.
+// Break will listen and serve on the http port and path of the options of the service.
+// it accepts 0 or 1 Options to override these; they are ignored if another goroutine is already paused.
+// If the options have a ServeMux then the paused goroutines are served by it at the path, without listening;
+// the port is then only used for the URL that is opened.
+// The opened explorer page will have a button "Resume" that unblocks the go-routine that started it.
+// Like the Break function, the call site is registered and can be disabled from the explorer page.
 func (s *service) Break(opts ...Options) {
+	location := callerLocation()
+	if !sites.hit(location, func(int) bool { return true }) {
+		return
+	}
 	if len(opts) > 0 {
 		s.explorer.options = &opts[0]
 	}
-	port := s.explorer.options.httpPort()
-	serveMux := s.explorer.options.serveMux()
-	rootPath := s.explorer.options.rootPath()
-	serveMux.Handle(rootPath, s)
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: serveMux,
-	}
-	s.httpServer = server
-	open(fmt.Sprintf("http://localhost:%d", port))
-	// this blocks until server is closed by resume operation.
-	server.ListenAndServe()
+	breaks.pause(s, location, s.explorer.options)
 }
 
 func (s *service) resume() {
-	if s.httpServer == nil {
+	if s.session == nil {
 		return
 	}
-	s.httpServer.Shutdown(context.Background())
-	s.httpServer = nil
+	breaks.resume(s.session.id)
 }
 
 // Start will listen and serve on the given http port and path.
@@ -106,6 +154,13 @@ func (s *service) Start(opts ...Options) {
 	port := s.explorer.options.httpPort()
 	serveMux := s.explorer.options.serveMux()
 	rootPath := s.explorer.options.rootPath()
+	if name := s.explorer.options.LayoutFile; name != "" {
+		s.explorer.mutex.Lock()
+		if err := s.explorer.loadLayoutFile(name); err != nil {
+			slog.Warn("[structexplorer] failed to load layout", "file", name, "err", err)
+		}
+		s.explorer.mutex.Unlock()
+	}
 	slog.Info(fmt.Sprintf("starting go struct explorer at http://localhost:%d%s on %v", port, rootPath, s.explorer.rootKeys()))
 	serveMux.Handle(rootPath, s)
 	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), serveMux); err != nil {
@@ -120,17 +175,41 @@ func (s *service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		// do not serve on favicon
 		if !strings.Contains(path.Base(r.URL.Path), ".") {
+			if r.URL.Query().Has("cell") {
+				s.serveCell(w, r)
+				return
+			}
+			if r.URL.Query().Has("csv") {
+				s.serveTrackCSV(w, r)
+				return
+			}
+			if s.debugMux != nil {
+				s.exploreExpvars()
+			}
 			s.serveIndex(w, r)
 		} else {
 			http.Error(w, "[structexplorer] not found", http.StatusNotFound)
 		}
 	case http.MethodPost:
 		s.serveInstructions(w, r)
+		s.saveLayout()
 	default:
 		http.Error(w, "[structexplorer] method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
+// saveLayout writes the layout to the file from the options, if set.
+func (s *service) saveLayout() {
+	name := s.explorer.options.LayoutFile
+	if name == "" {
+		return
+	}
+	defer s.protect()()
+	if err := s.explorer.saveLayoutFile(name); err != nil {
+		slog.Warn("[structexplorer] failed to save layout", "file", name, "err", err)
+	}
+}
+
 // protect locks the mutex and returns the unlock function for defer calling it.
 func (s *service) protect() func() {
 	// protect explorer state from concurrent access
@@ -138,26 +217,161 @@ func (s *service) protect() func() {
 	return s.explorer.mutex.Unlock
 }
 
-func (s *service) serveIndex(w http.ResponseWriter, _ *http.Request) {
-	defer s.protect()()
+// readValues locks the values for reading and returns the unlock function for defer calling it.
+func (s *service) readValues() func() {
+	s.values.RLock()
+	return s.values.RUnlock
+}
+
+// writeValues locks the values for changing them and returns the unlock function for defer calling it.
+func (s *service) writeValues() func() {
+	s.values.Lock()
+	return s.values.Unlock
+}
+
+// serveIndex writes the page. The cells are built outside the lock from a snapshot
+// such that calls to Explore are not blocked by rendering large values.
+func (s *service) serveIndex(w http.ResponseWriter, r *http.Request) {
+	unlock := s.protect()
+	locked := true
+	defer func() {
+		if locked {
+			unlock()
+		}
+	}()
+
+	workspace, e := s.workspaceFor(r)
+	if sessionID(r) == "" {
+		http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: newSessionID(), Path: "/", HttpOnly: true})
+	}
+
+	// link with workspace or paths to explore?
+	if query := r.URL.Query(); query.Has("explore") || query.Has("workspace") {
+		// only switch to a workspace that exists
+		if query.Get("workspace") == workspace {
+			http.SetCookie(w, &http.Cookie{Name: workspaceCookieName, Value: url.QueryEscape(workspace), Path: "/"})
+		}
+		e.applyExploreQuery(query["explore"])
+		query.Del("explore")
+		query.Del("workspace")
+		clean := *r.URL
+		clean.RawQuery = query.Encode()
+		http.Redirect(w, r, clean.String(), http.StatusSeeOther)
+		return
+	}
 
 	w.Header().Set("content-type", "text/html")
 
 	builder := newIndexDataBuilder()
-	builder.isBreaking = s.httpServer != nil
+	builder.layoutKey = strconv.FormatInt(s.startedAt.UnixNano(), 10)
+	builder.workspace = workspace
+	builder.workspaces = s.workspaceNames()
+	builder.breakSites, builder.breakSitesEnabled = sites.siteEntries()
+	builder.watches = s.watchEntries(time.Now())
+	builder.tracks = s.trackEntries()
+	comparisons := s.compareSnapshots()
+	builder.debugLinks = s.debugLinks()
+	if s.session != nil {
+		builder.isBreaking = true
+		builder.breaks = breaks.breakEntries(s.session.id)
+		builder.stack = s.session.stack
+	}
+
+	builder.deadline = time.Now().Add(e.options.renderTimeBudget())
+	builder.maxEntries = e.options.renderEntryBudget()
+	builder.buildHeader(e)
+	cells := e.snapshot()
+	unlock()
+	locked = false
+
+	func() {
+		defer s.readValues()()
+		builder.buildCells(cells)
+		builder.data.Comparisons = compareEntries(comparisons)
+	}()
+	if len(builder.showZeros) > 0 {
+		func() {
+			defer s.protect()()
+			e.showZerosAt(builder.showZeros)
+		}()
+	}
 
-	if err := s.indexTemplate.Execute(w, s.explorer.buildIndexData(builder)); err != nil {
+	if err := s.indexTemplate.Execute(w, builder.data); err != nil {
 		slog.Error("failed to execute template", "err", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// serveTrackCSV writes the samples of the track given by the "csv" query parameter.
+func (s *service) serveTrackCSV(w http.ResponseWriter, r *http.Request) {
+	defer s.protect()()
+	w.Header().Set("content-type", "text/csv")
+	w.Header().Set("content-disposition", `attachment; filename="track.csv"`)
+	if err := s.writeTrackCSV(w, r.URL.Query().Get("csv")); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	}
+}
+
+// parseCellLocation parses "<row>,<column>".
+func parseCellLocation(s string) (row, column int, ok bool) {
+	_, err := fmt.Sscanf(s, "%d,%d", &row, &column)
+	return row, column, err == nil
+}
+
+// serveCell writes the HTML of one cell, given by the "cell" query parameter as "<row>,<column>".
+// It is used by the Browser to load cells that were left out of the page.
+func (s *service) serveCell(w http.ResponseWriter, r *http.Request) {
+	row, column, ok := parseCellLocation(r.URL.Query().Get("cell"))
+	if !ok {
+		http.Error(w, "[structexplorer] invalid cell", http.StatusBadRequest)
+		return
+	}
+	unlock := s.protect()
+	_, e := s.workspaceFor(r)
+	access := e.objectAt(row, column)
+	unlock()
+	if access.isEmpty() {
+		http.Error(w, "[structexplorer] no such cell", http.StatusNotFound)
+		return
+	}
+
+	builder := newIndexDataBuilder()
+	cell := cellSnapshot{row: row, column: column, access: access}
+	func() {
+		defer s.readValues()()
+		builder.buildCell(cell)
+	}()
+	if len(builder.showZeros) > 0 {
+		func() {
+			defer s.protect()()
+			e.showZerosAt(builder.showZeros)
+		}()
+	}
+	w.Header().Set("content-type", "text/html")
+	if err := s.indexTemplate.ExecuteTemplate(w, "struct", builder.data.Rows[row].Cells[column]); err != nil {
+		slog.Error("failed to execute template", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 // Explore adds or replaces a new entry (next available row in column 0) for a value if it can be explored.
 func (s *service) Explore(label string, value any, options ...ExploreOption) Service {
 	defer s.protect()()
 
-	if !canExplore(value) {
+	locker := lockerOf(value, options)
+	if isCopyOnExplore(options) {
+		unlock := objectAccess{locker: locker}.lock()
+		value = deepCopy(value)
+		unlock()
+		// the copy is not shared
+		locker = nil
+	}
+
+	unlock := objectAccess{locker: locker}.lock()
+	explorable := canExplore(value)
+	unlock()
+	if !explorable {
 		slog.Debug("value can not be explored", "value", value)
 		return s
 	}
@@ -167,46 +381,42 @@ func (s *service) Explore(label string, value any, options ...ExploreOption) Ser
 		object:    value,
 		path:      []string{""},
 		label:     label,
+		rootLabel: label,
 		hideZeros: true,
 		typeName:  fmt.Sprintf("%T", value),
+		locker:    locker,
 	}
 
-	// are we replacing an object access?
-	_, oldRow, oldcolumn, ok := s.explorer.rootAccessWithLabel(label)
-	if ok {
-		s.explorer.putObjectAt(oldRow, oldcolumn, oa)
-		return s
-	}
+	// roots are shared by all workspaces
+	s.eachWorkspace(func(e *explorer) {
+		// are we replacing an object access?
+		_, oldRow, oldcolumn, ok := e.rootAccessWithLabel(label)
+		if ok {
+			e.replaceRoot(oldRow, oldcolumn, oa)
+			return
+		}
 
-	// add as new
-	row, column := 0, 0
-	placement := Row(0)
-	if len(options) > 0 {
-		placement = options[0]
-		row, column = options[0].placement(s.explorer)
-	}
-	s.explorer.putObjectStartingAt(row, column, oa, placement)
+		// add as new
+		row, column := 0, 0
+		placement := placementOf(options, ExploreOption{})
+		if placement.placement == nil {
+			placement = Row(0)
+		} else {
+			row, column = placement.placement(e)
+		}
+		e.putObjectStartingAt(row, column, oa, placement)
+	})
 	return s
 }
 
-// Dump writes an HTML file for displaying the current state of the explorer and its entries.
-func (s *service) Dump(optionalFilename ...string) {
-	defer s.protect()()
-
+// Dump writes a file for displaying the current state of the explorer and its entries.
+// The format is HTML unless the extension of the filename is .json, .yaml, .yml or .md.
+func (s *service) Dump(optionalFilename ...string) error {
 	fName := "structexplorer.html"
 	if len(optionalFilename) > 0 && optionalFilename[0] != "" {
 		fName = optionalFilename[0]
 	}
-	out, err := os.Create(fName)
-	if err != nil {
-		slog.Error("failed to create dump file", "err", err)
-	}
-	defer out.Close()
-	b := newIndexDataBuilder()
-	b.notLive = true
-	if err := s.indexTemplate.Execute(out, s.explorer.buildIndexData(b)); err != nil {
-		slog.Error("failed to execute template", "err", err)
-	}
+	return s.dumpFile(fName, formatOfFile(fName))
 }
 
 type uiInstruction struct {
@@ -226,7 +436,12 @@ func (s *service) serveInstructions(w http.ResponseWriter, r *http.Request) {
 
 	defer s.protect()()
 
-	fromAccess := s.explorer.objectAt(cmd.Row, cmd.Column)
+	if cmd.Action == "newWorkspace" {
+		s.serveNewWorkspace(w, cmd.Selections)
+		return
+	}
+	_, e := s.workspaceForChange(r)
+	fromAccess := e.objectAt(cmd.Row, cmd.Column)
 	toRow := cmd.Row
 	toColumn := cmd.Column
 	switch cmd.Action {
@@ -241,79 +456,206 @@ func (s *service) serveInstructions(w http.ResponseWriter, r *http.Request) {
 			toRow = 0
 		}
 	case "remove":
-		if s.explorer.canRemoveObjectAt(cmd.Row, cmd.Column) {
-			s.explorer.removeObjectAt(cmd.Row, cmd.Column)
+		if e.canRemoveObjectAt(cmd.Row, cmd.Column) {
+			e.removeObjectAt(cmd.Row, cmd.Column)
 		} else {
 			slog.Warn("[structexplorer] cannot remove root struct", "object", fromAccess.label, "row", cmd.Row, "column", cmd.Column)
 		}
 		return
 	case "toggleZeros":
-		s.explorer.updateObjectAt(cmd.Row, cmd.Column, func(access objectAccess) objectAccess {
+		e.updateObjectAt(cmd.Row, cmd.Column, func(access objectAccess) objectAccess {
 			access.hideZeros = !access.hideZeros
 			return access
 		})
 		return
 	case "clear":
-		s.explorer.removeNonRootObjects()
+		e.removeNonRootObjects()
+		return
+	case "watch":
+		for _, each := range cmd.Selections {
+			if _, err := parsePath(each, e.rootLabels()); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.watch(each, each)
+		}
+		return
+	case "unwatch":
+		for _, each := range cmd.Selections {
+			s.unwatch(each)
+		}
+		return
+	case "track":
+		// numeric fields selected in a cell
+		if fromAccess.rootLabel == "" {
+			http.Error(w, "cannot track values that are not explored from a root", http.StatusBadRequest)
+			return
+		}
+		for _, each := range cmd.Selections {
+			s.track(formatPath(fromAccess.object, fromAccess.rootLabel, append(append([]string{}, fromAccess.path...), each)))
+		}
+		return
+	case "trackPath":
+		for _, each := range cmd.Selections {
+			if _, err := e.numberForPath(each); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.track(each)
+		}
+		return
+	case "untrack":
+		for _, each := range cmd.Selections {
+			s.untrack(each)
+		}
+		return
+	case "compare":
+		// with the cell at "row,column" in the selection
+		var other objectAccess
+		if len(cmd.Selections) == 1 {
+			if row, column, ok := parseCellLocation(cmd.Selections[0]); ok {
+				other = e.objectAt(row, column)
+			}
+		}
+		left, right := other.explorePath(), fromAccess.explorePath()
+		if left == "" || right == "" {
+			http.Error(w, "can only compare values that are explored from a root", http.StatusBadRequest)
+			return
+		}
+		s.compare(left, right)
+		return
+	case "align", "unalign", "uncompare":
+		// selections are the left and right path expressions
+		if len(cmd.Selections) != 2 {
+			http.Error(w, "left and right path expected", http.StatusBadRequest)
+			return
+		}
+		if cmd.Action == "uncompare" {
+			s.uncompare(cmd.Selections[0], cmd.Selections[1])
+			return
+		}
+		var options []CompareOption
+		if cmd.Action == "align" {
+			options = append(options, AlignElements())
+		}
+		s.compare(cmd.Selections[0], cmd.Selections[1], options...)
+		return
+	case "sort":
+		sortBy := firstSelection(cmd.Selections)
+		if !isSortBy(sortBy) {
+			http.Error(w, fmt.Sprintf("cannot sort by %q", sortBy), http.StatusBadRequest)
+			return
+		}
+		e.updateObjectAt(cmd.Row, cmd.Column, func(access objectAccess) objectAccess {
+			access.sortBy = sortBy
+			return access
+		})
+		return
+	case "filter":
+		filter := firstSelection(cmd.Selections)
+		if _, err := entryFilter(filter); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		e.updateObjectAt(cmd.Row, cmd.Column, func(access objectAccess) objectAccess {
+			access.filter = filter
+			return access
+		})
+		return
+	case "explorePath":
+		// path expressions entered on the page
+		for _, each := range cmd.Selections {
+			if err := e.explorePath(each); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		return
+	case "restoreLayout":
+		// layout saved in the browser
+		for _, each := range cmd.Selections {
+			cells := []layoutCell{}
+			if err := json.Unmarshal([]byte(each), &cells); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			e.restoreLayout(cells)
+		}
 		return
 	case "resume":
 		s.resume()
 		return
+	case "resumeAll":
+		breaks.resumeAll()
+		return
+	case "exploreFrame":
+		s.exploreFrames(e, cmd.Selections)
+		return
+	case "extendBreak":
+		if s.session != nil {
+			breaks.extend(s.session.id)
+		}
+		return
+	case "toggleBreakSite":
+		for _, each := range cmd.Selections {
+			sites.toggle(each)
+		}
+		return
+	case "toggleBreakSites":
+		sites.toggleAll()
+		return
 
 	default:
 		slog.Warn("[structexplorer] invalid direction", "action", cmd.Action)
 		http.Error(w, "invalid action", http.StatusBadRequest)
 		return
 	}
+	defer fromAccess.lock()()
 	for _, each := range cmd.Selections {
-		newPath := append(append([]string{}, fromAccess.path...), each)
-		oa := objectAccess{
-			object:    fromAccess.object,
-			path:      newPath,
-			label:     strings.Join(newPath, "."),
-			hideZeros: true,
+		if oa, ok := fromAccess.child(each); ok {
+			e.putObjectStartingAt(toRow, toColumn, oa, Row(toRow))
 		}
-		var v any
-		// handle range key
-		if isIntervalKey(each) {
-			oa.sliceRange = parseInterval(each)
-			// accesses same object, and no need to check canExplore
-			v = fromAccess.object
-		} else {
-			// other keys
-			v = oa.Value()
-			if !canExplore(v) {
-				slog.Warn("[structexplorer] cannot explore this", "value", v, "path", oa.label, "type", fmt.Sprintf("%T", v))
-				continue
-			}
-		}
-		oa.typeName = fmt.Sprintf("%T", v)
-		s.explorer.putObjectStartingAt(toRow, toColumn, oa, Row(toRow))
 	}
 }
 
-func (s *service) ExplorePath(newPath string, options ...ExploreOption) Service {
-	if newPath == "" {
-		return s
+// firstSelection returns the first selection or empty if there are none.
+func firstSelection(selections []string) string {
+	if len(selections) == 0 {
+		return ""
 	}
-	pathTokens := strings.Split(newPath, ".")
-	// find root
-	root, row, col, ok := s.explorer.rootAccessWithLabel(pathTokens[0])
-	if !ok {
-		slog.Warn("[structexplorer] object not found", "label", pathTokens[0])
-		return s
+	return selections[0]
+}
+
+// exploreFrames adds a cell for each stack frame, by index, of the paused goroutine.
+// pre: protected
+func (s *service) exploreFrames(e *explorer, indices []string) {
+	if s.session == nil {
+		return
 	}
-	oa := objectAccess{
-		object:    root.object,
-		path:      pathTokens[1:],
-		label:     newPath,
-		hideZeros: true,
+	for _, each := range indices {
+		i, err := strconv.Atoi(each)
+		if err != nil || i < 0 || i >= len(s.session.stack) {
+			slog.Warn("[structexplorer] invalid stack frame", "index", each)
+			continue
+		}
+		frame := s.session.stack[i]
+		label := fmt.Sprintf("#%d %s", i, path.Base(frame.Function))
+		e.putObjectStartingAt(0, e.nextFreeColumn(0), objectAccess{
+			object:    frame,
+			path:      []string{""},
+			label:     label,
+			hideZeros: true,
+			typeName:  fmt.Sprintf("%T", frame),
+		}, Row(0))
 	}
-	placement := Row(row)
-	if len(options) > 0 {
-		placement = options[0]
+}
+
+// ExplorePath adds a new entry for a value at the specified access path unless it cannot be explored.
+func (s *service) ExplorePath(newPath string, options ...ExploreOption) Service {
+	defer s.protect()()
+	if err := s.explorer.explorePath(newPath, options...); err != nil {
+		slog.Warn("[structexplorer] cannot explore path", "err", err)
 	}
-	s.explorer.putObjectStartingAt(row, col, oa, placement)
 	return s
 }
 
@@ -1,10 +1,16 @@
 package structexplorer
 
 import (
+	"log/slog"
 	"net/http"
+	"os"
 	"path"
+	"time"
 )
 
+// breakTimeoutEnvName is the name of the environment variable with the default for Options.BreakTimeout, e.g. "30s".
+const breakTimeoutEnvName = "STRUCTEXPLORER_BREAK_TIMEOUT"
+
 // Options can be used to configure a Service on startup.
 type Options struct {
 	// Uses 5656 as the default
@@ -13,6 +19,27 @@ type Options struct {
 	ServeMux *http.ServeMux
 	// Uses "/" as default
 	HTTPBasePath string
+	// If set then a paused goroutine is resumed automatically after this duration.
+	// Uses the environment variable STRUCTEXPLORER_BREAK_TIMEOUT as default, otherwise there is no timeout.
+	BreakTimeout time.Duration
+	// If set then the layout of explored values is restored from this JSON file on start
+	// and saved to it after each change.
+	LayoutFile string
+	// Maximum time to build the cells of a page; the remaining cells are loaded by the Browser afterwards.
+	// Uses 200ms as default.
+	RenderTimeBudget time.Duration
+	// Maximum number of entries of all cells of a page; the remaining cells are loaded by the Browser afterwards.
+	// Uses 5000 as default.
+	RenderEntryBudget int
+	// Interval at which tracked values are sampled, see Service.Track.
+	// Uses 1s as default.
+	TrackInterval time.Duration
+	// Maximum number of recent samples kept per tracked value.
+	// Uses 300 as default.
+	TrackSamples int
+	// If set then an agent accepts the edit operation, which changes values of the running program,
+	// including unexported fields. Anyone who can reach the agent can then change them; off by default.
+	AllowEdit bool
 }
 
 func (o *Options) rootPath() string {
@@ -35,3 +62,47 @@ func (o *Options) serveMux() *http.ServeMux {
 	}
 	return o.ServeMux
 }
+
+func (o *Options) breakTimeout() time.Duration {
+	if o.BreakTimeout != 0 {
+		return o.BreakTimeout
+	}
+	env := os.Getenv(breakTimeoutEnvName)
+	if env == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(env)
+	if err != nil {
+		slog.Warn("[structexplorer] invalid break timeout", "env", breakTimeoutEnvName, "value", env, "err", err)
+		return 0
+	}
+	return d
+}
+
+func (o *Options) renderTimeBudget() time.Duration {
+	if o.RenderTimeBudget == 0 {
+		return 200 * time.Millisecond
+	}
+	return o.RenderTimeBudget
+}
+
+func (o *Options) renderEntryBudget() int {
+	if o.RenderEntryBudget == 0 {
+		return 5000
+	}
+	return o.RenderEntryBudget
+}
+
+func (o *Options) trackInterval() time.Duration {
+	if o.TrackInterval == 0 {
+		return time.Second
+	}
+	return o.TrackInterval
+}
+
+func (o *Options) trackSamples() int {
+	if o.TrackSamples == 0 {
+		return 300
+	}
+	return o.TrackSamples
+}
@@ -3,7 +3,9 @@ package structexplorer
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -67,6 +69,36 @@ func TestServiceExplore(t *testing.T) {
 	}
 	s.Dump()
 }
+func TestServiceExploreReplace(t *testing.T) {
+	s := NewService("config", map[string]any{"db": map[string]any{"host": "a"}}).(*service)
+	s.ExplorePath(`config["db"]`)
+	s.Explore("config", map[string]any{"db": map[string]any{"host": "b"}})
+	if !s.explorer.hasExplorePath(`config["db"]`) {
+		t.Fatal("explored path expected")
+	}
+	for _, cols := range s.explorer.accessMap {
+		for _, each := range cols {
+			if each.isRoot {
+				continue
+			}
+			if got, want := each.Value().(map[string]any)["host"], "b"; got != want {
+				t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+			}
+		}
+	}
+	// the type of an explored value can change too
+	s.Explore("config", map[string]any{"db": []string{"a", "b"}})
+	for _, cols := range s.explorer.accessMap {
+		for _, each := range cols {
+			if each.isRoot {
+				continue
+			}
+			if got, want := each.typeName, "[]string"; got != want {
+				t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+			}
+		}
+	}
+}
 func TestServiceExploreWithOption(t *testing.T) {
 	s := NewService().(*service)
 	s.Explore("now", time.Now(), RowColumn(2, 2))
@@ -82,3 +114,108 @@ func TestServicEmptyFollow(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestServeExploreQuery(t *testing.T) {
+	s := NewService("now", time.Now()).(*service)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/?explore=now.loc&explore="+url.QueryEscape("2,1,now.wall"), nil)
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusSeeOther; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := rec.Header().Get("location"), "/"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.explorer.objectAt(0, 1).explorePath(), "now.loc"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.explorer.objectAt(2, 1).explorePath(), "now.wall"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// same link again does not add cells
+	s.ServeHTTP(httptest.NewRecorder(), req)
+	if got, want := shareQuery(s.explorer.layout()), "explore=0%2C1%2Cnow.loc&explore=2%2C1%2Cnow.wall"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestServeLazyCell(t *testing.T) {
+	type big struct{ Items []int }
+	s := NewService("a", big{Items: make([]int, 20)}, "b", big{Items: []int{1, 2}}).(*service)
+	s.explorer.options.RenderEntryBudget = 1
+	s.ExplorePath("a.Items")
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(rec, req)
+	if got, want := strings.Count(rec.Body.String(), `class="col lazy"`), 2; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/?cell=0,1", nil)
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `id="id0-1"`) || strings.Contains(body, "<html") {
+		t.Errorf("unexpected cell %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/?cell=9,9", nil)
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusNotFound; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+// blockingStringer blocks String until released, to render a page that is slow to build.
+type blockingStringer struct {
+	started  chan struct{}
+	once     *sync.Once
+	released chan struct{}
+}
+
+func (b blockingStringer) String() string {
+	b.once.Do(func() { close(b.started) })
+	<-b.released
+	return "released"
+}
+
+func TestServeIndexDoesNotHoldLockWhileBuilding(t *testing.T) {
+	slow := blockingStringer{started: make(chan struct{}), once: new(sync.Once), released: make(chan struct{})}
+	defer close(slow.released)
+	s := NewService("slow", &struct{ Value blockingStringer }{Value: slow}).(*service)
+
+	served := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		s.ServeHTTP(rec, req)
+		served <- rec
+	}()
+	select {
+	case <-slow.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("page is not built")
+	}
+
+	// the page is being built
+	explored := make(chan bool)
+	go func() {
+		s.Explore("other", struct{ A int }{A: 1})
+		explored <- true
+	}()
+	select {
+	case <-explored:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Explore is blocked by building the page")
+	}
+	select {
+	case <-served:
+		t.Fatal("page must still be building")
+	default:
+	}
+}
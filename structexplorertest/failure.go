@@ -0,0 +1,68 @@
+// Package structexplorertest has helpers to explore values in tests.
+package structexplorertest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/structexplorer"
+)
+
+// onFailureEnvName is the name of the environment variable that enables ExploreOnFailure.
+// Its value is either "break" or "dump".
+const onFailureEnvName = "STRUCTEXPLORER_ON_FAILURE"
+
+// artifactsEnvName is the name of the environment variable with the directory to write dumps to.
+// If not set then the directory "structexplorer" in the temporary directory of the OS is used;
+// unlike the directory of t.TempDir, it is not removed after the test.
+const artifactsEnvName = "STRUCTEXPLORER_ARTIFACTS"
+
+// ExploreOnFailure registers a cleanup function for the test that explores the values if the test has failed.
+// What happens depends on the environment variable STRUCTEXPLORER_ON_FAILURE:
+//
+//   - "break" : starts a Break session; use STRUCTEXPLORER_BREAK_TIMEOUT to avoid blocking forever.
+//   - "dump"  : writes an HTML file into STRUCTEXPLORER_ARTIFACTS (or $TMPDIR/structexplorer) and logs its path.
+//
+// If the variable is not set then nothing happens.
+func ExploreOnFailure(t testing.TB, labelValuePairs ...any) {
+	t.Helper()
+	mode := os.Getenv(onFailureEnvName)
+	if mode == "" {
+		return
+	}
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		s := structexplorer.NewService(labelValuePairs...)
+		switch mode {
+		case "break":
+			t.Log("[structexplorer] test failed, breaking to explore values")
+			s.Break()
+		case "dump":
+			dir := os.Getenv(artifactsEnvName)
+			if dir == "" {
+				dir = filepath.Join(os.TempDir(), "structexplorer")
+			}
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				t.Log("[structexplorer] failed to create artifacts directory", err)
+				return
+			}
+			name := filepath.Join(dir, dumpFileName(t.Name()))
+			if err := s.Dump(name); err != nil {
+				t.Log("[structexplorer] failed to dump values", err)
+				return
+			}
+			t.Log("[structexplorer] test failed, values dumped to", name)
+		default:
+			t.Logf("[structexplorer] invalid value %q for %s, use break or dump", mode, onFailureEnvName)
+		}
+	})
+}
+
+// dumpFileName returns a filename for a test name which can contain slashes for subtests.
+func dumpFileName(testName string) string {
+	return strings.NewReplacer("/", "_", " ", "_").Replace(testName) + ".html"
+}
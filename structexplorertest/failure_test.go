@@ -0,0 +1,64 @@
+package structexplorertest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// failedTB is a failed test that records cleanups and logs.
+type failedTB struct {
+	testing.TB
+	cleanups []func()
+	logs     []string
+}
+
+func (f *failedTB) Failed() bool      { return true }
+func (f *failedTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *failedTB) Log(args ...any)   { f.logs = append(f.logs, fmt.Sprintln(args...)) }
+func (f *failedTB) Name() string      { return "TestFailed/sub" }
+func (f *failedTB) Logf(s string, args ...any) {
+	f.logs = append(f.logs, fmt.Sprintf(s, args...))
+}
+
+func TestExploreOnFailureDump(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(onFailureEnvName, "dump")
+	t.Setenv(artifactsEnvName, dir)
+	tb := &failedTB{TB: t}
+	ExploreOnFailure(tb, "now", time.Now())
+	if got, want := len(tb.cleanups), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	tb.cleanups[0]()
+	if _, err := os.Stat(filepath.Join(dir, "TestFailed_sub.html")); err != nil {
+		t.Error(err)
+	}
+	if got, want := len(tb.logs), 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestExploreOnFailureDumpDefaultDir(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv(onFailureEnvName, "dump")
+	t.Setenv(artifactsEnvName, "")
+	tb := &failedTB{TB: t}
+	ExploreOnFailure(tb, "now", time.Now())
+	tb.cleanups[0]()
+	if _, err := os.Stat(filepath.Join(tmp, "structexplorer", "TestFailed_sub.html")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExploreOnFailureDisabled(t *testing.T) {
+	t.Setenv(onFailureEnvName, "")
+	tb := &failedTB{TB: t}
+	ExploreOnFailure(tb, "now", time.Now())
+	if got, want := len(tb.cleanups), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
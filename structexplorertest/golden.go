@@ -0,0 +1,86 @@
+package structexplorertest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/structexplorer"
+)
+
+// updateGoldenEnvName is the name of the environment variable to write the golden files of AssertGolden instead of comparing them.
+const updateGoldenEnvName = "STRUCTEXPLORER_UPDATE_GOLDEN"
+
+// updateGoldenFlag is the name of a test flag, defined by the test package itself, that also writes the golden files.
+const updateGoldenFlag = "update"
+
+// AssertGolden renders the value as a text tree, see structexplorer.GoldenText,
+// and compares it to the file testdata/<name>.golden.
+// If the environment variable STRUCTEXPLORER_UPDATE_GOLDEN is "1", or the test package defines
+// an -update flag and the test is run with it, then the file is written instead.
+func AssertGolden(t testing.TB, name string, value any, opts ...structexplorer.GoldenOption) {
+	t.Helper()
+	got := structexplorer.GoldenText(name, value, opts...)
+	fileName := filepath.Join("testdata", name+".golden")
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fileName, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Log("[structexplorer] updated golden file", fileName)
+		return
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("[structexplorer] cannot read golden file, run the test with %s=1 to create it: %v", updateGoldenEnvName, err)
+		return
+	}
+	if diff := goldenDiff(string(data), got); diff != "" {
+		t.Errorf("[structexplorer] value differs from golden file %s, run the test with %s=1 to update it:\n%s", fileName, updateGoldenEnvName, diff)
+	}
+}
+
+// updateGolden returns whether golden files must be written.
+// The flag is looked up when called; this package does not define it.
+func updateGolden() bool {
+	if os.Getenv(updateGoldenEnvName) == "1" {
+		return true
+	}
+	f := flag.Lookup(updateGoldenFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+// goldenDiff returns the lines that differ, or an empty string if equal.
+func goldenDiff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	b := new(strings.Builder)
+	count := 0
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		w, g := lineAt(wantLines, i), lineAt(gotLines, i)
+		if w == g {
+			continue
+		}
+		if count == 10 {
+			b.WriteString("...\n")
+			break
+		}
+		count++
+		fmt.Fprintf(b, "line %d:\n  want: %s\n  got:  %s\n", i+1, w, g)
+	}
+	return b.String()
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return "<missing>"
+}
@@ -0,0 +1,79 @@
+package structexplorertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/structexplorer"
+)
+
+type goldenOrder struct {
+	ID        int
+	CreatedAt time.Time
+	Customer  *goldenCustomer
+	Lines     []goldenLine
+	Tags      map[string]bool
+}
+
+type goldenCustomer struct {
+	Name   string
+	Orders []*goldenOrder
+}
+
+type goldenLine struct {
+	SKU      string
+	Quantity int
+}
+
+func newGoldenOrder() *goldenOrder {
+	order := &goldenOrder{
+		ID:        7,
+		CreatedAt: time.Now(),
+		Lines:     []goldenLine{{SKU: "x", Quantity: 2}, {SKU: "y"}},
+		Tags:      map[string]bool{"item10": true, "item2": false, "gift": true},
+	}
+	order.Customer = &goldenCustomer{Name: "alice", Orders: []*goldenOrder{order}}
+	return order
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, "golden_order", newGoldenOrder(), structexplorer.Redact("CreatedAt", `golden_order.Lines[1].SKU`))
+}
+
+type goldenRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *goldenRecorder) Helper() {}
+
+func (r *goldenRecorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGoldenDiffers(t *testing.T) {
+	t.Setenv(updateGoldenEnvName, "")
+	order := newGoldenOrder()
+	order.Lines[0].Quantity = 3
+	rec := &goldenRecorder{TB: t}
+	AssertGolden(rec, "golden_order", order, structexplorer.Redact("CreatedAt", `golden_order.Lines[1].SKU`))
+	if got, want := len(rec.errors), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !strings.Contains(rec.errors[0], "want:       Quantity: 2\n  got:        Quantity: 3") {
+		t.Errorf("unexpected diff: %s", rec.errors[0])
+	}
+}
+
+func TestUpdateGolden(t *testing.T) {
+	t.Setenv(updateGoldenEnvName, "")
+	if updateGolden() {
+		t.Error("no update expected")
+	}
+	t.Setenv(updateGoldenEnvName, "1")
+	if !updateGolden() {
+		t.Error("update expected")
+	}
+}
@@ -0,0 +1,226 @@
+package structexplorer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sample is a value of a tracked path expression at a point in time.
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+// ring holds the most recent samples, up to its capacity.
+type ring struct {
+	samples []sample
+	next    int // index to write the next sample if full
+}
+
+func newRing(capacity int) *ring {
+	return &ring{samples: make([]sample, 0, capacity)}
+}
+
+func (r *ring) add(s sample) {
+	if len(r.samples) < cap(r.samples) {
+		r.samples = append(r.samples, s)
+		return
+	}
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+}
+
+// ordered returns the samples, oldest first.
+func (r *ring) ordered() []sample {
+	return append(append([]sample{}, r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// track is a path expression with a numeric value that is sampled on an interval.
+type track struct {
+	expr    string
+	samples *ring
+	err     error // of the last sample
+}
+
+// Track samples the numeric value at the path expression on an interval, see Options.TrackInterval.
+// The page shows its recent history as a sparkline with its minimum, maximum and last value.
+func (s *service) Track(path string) Service {
+	defer s.protect()()
+	s.track(path)
+	return s
+}
+
+// pre: protected
+func (s *service) track(path string) {
+	for _, each := range s.tracks {
+		if each.expr == path {
+			return
+		}
+	}
+	t := &track{expr: path, samples: newRing(s.explorer.options.trackSamples())}
+	t.sample(s.explorer, time.Now())
+	s.tracks = append(s.tracks, t)
+	if !s.sampling {
+		s.sampling = true
+		go s.sampleLoop(s.explorer.options.trackInterval())
+	}
+}
+
+// pre: protected
+func (s *service) untrack(path string) {
+	for i, each := range s.tracks {
+		if each.expr == path {
+			s.tracks = append(s.tracks[:i], s.tracks[i+1:]...)
+			return
+		}
+	}
+}
+
+// sampleLoop samples all tracks until there are none.
+func (s *service) sampleLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		unlock := s.protect()
+		if len(s.tracks) == 0 {
+			s.sampling = false
+			unlock()
+			return
+		}
+		s.sampleTracks(now)
+		unlock()
+	}
+}
+
+// pre: protected
+func (s *service) sampleTracks(now time.Time) {
+	for _, each := range s.tracks {
+		each.sample(s.explorer, now)
+	}
+}
+
+// pre: protected
+func (t *track) sample(e *explorer, now time.Time) {
+	var f float64
+	f, t.err = e.numberForPath(t.expr)
+	if t.err != nil {
+		return
+	}
+	t.samples.add(sample{at: now, value: f})
+}
+
+// toFloat returns the value of an integer or float, after dereferencing pointers.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return 0, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+const (
+	sparklineWidth  = 120
+	sparklineHeight = 24
+)
+
+// trackEntries returns the history of all tracks in order of registration.
+// pre: protected
+func (s *service) trackEntries() (list []trackEntry) {
+	for _, each := range s.tracks {
+		entry := trackEntry{Path: each.expr}
+		if each.err != nil {
+			entry.Error = each.err.Error()
+		}
+		samples := each.samples.ordered()
+		if len(samples) > 0 {
+			low, high := math.Inf(1), math.Inf(-1)
+			for _, each := range samples {
+				low = math.Min(low, each.value)
+				high = math.Max(high, each.value)
+			}
+			entry.Min = formatSample(low)
+			entry.Max = formatSample(high)
+			entry.Last = formatSample(samples[len(samples)-1].value)
+			entry.Points = sparklinePoints(samples, low, high)
+			entry.Count = len(samples)
+		}
+		list = append(list, entry)
+	}
+	return
+}
+
+// sparklinePoints returns the points of an SVG polyline, oldest sample on the left.
+func sparklinePoints(samples []sample, low, high float64) string {
+	b := new(strings.Builder)
+	for i, each := range samples {
+		x := 0.0
+		if len(samples) > 1 {
+			x = float64(i) * sparklineWidth / float64(len(samples)-1)
+		}
+		y := sparklineHeight / 2.0
+		if high > low {
+			y = sparklineHeight - (each.value-low)/(high-low)*sparklineHeight
+		}
+		fmt.Fprintf(b, "%.1f,%.1f ", x, y)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func formatSample(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// writeTrackCSV writes the samples of the track with the path expression, oldest first.
+// pre: protected
+func (s *service) writeTrackCSV(w io.Writer, path string) error {
+	for _, each := range s.tracks {
+		if each.expr != path {
+			continue
+		}
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"time", path})
+		for _, sample := range each.samples.ordered() {
+			cw.Write([]string{sample.at.Format(time.RFC3339Nano), formatSample(sample.value)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("path %q is not tracked", path)
+}
+
+// numberForPath returns the value at the path expression as a float, it must be an integer or float.
+// pre: protected
+func (e *explorer) numberForPath(expr string) (float64, error) {
+	parsed, err := parsePath(expr, e.rootLabels())
+	if err != nil {
+		return 0, err
+	}
+	root, _, _, _ := e.rootAccessWithLabel(parsed.root)
+	defer root.lock()()
+	v, err := resolveAccessPath(root.object, parsed.keys)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return 0, fmt.Errorf("value of type %T is not a number", v)
+	}
+	return f, nil
+}
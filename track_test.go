@@ -0,0 +1,130 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingOrdered(t *testing.T) {
+	r := newRing(3)
+	for i := range 5 {
+		r.add(sample{value: float64(i)})
+	}
+	list := r.ordered()
+	if got, want := len(list), 3; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[0].value+10*list[2].value, 2.0+40.0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+type trackedGauges struct {
+	Count   int
+	Load    *float64
+	Name    string
+	Buckets map[string]uint
+}
+
+func TestTrackEntries(t *testing.T) {
+	load := 0.5
+	g := &trackedGauges{Load: &load, Name: "g", Buckets: map[string]uint{"a": 1}}
+	s := NewService("g", g).(*service)
+	s.explorer.options.TrackInterval = time.Hour // sampled by test only
+	s.Track("g.Count")
+	s.Track("g.Load")
+	s.Track("g.Name")
+	s.Track("g.Count") // ignored
+
+	start := time.Now()
+	for i := 1; i <= 3; i++ {
+		g.Count = i * 10
+		s.sampleTracks(start.Add(time.Duration(i) * time.Second))
+	}
+	list := s.trackEntries()
+	if got, want := len(list), 3; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	count := list[0]
+	// one sample when tracked, three sampled
+	if got, want := count.Count, 4; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := count.Min+"/"+count.Max+"/"+count.Last, "0/30/30"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := count.Points, "0.0,24.0 40.0,16.0 80.0,8.0 120.0,0.0"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[1].Last, "0.5"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[2].Error, "value of type string is not a number"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	b := new(strings.Builder)
+	if err := s.writeTrackCSV(b, "g.Count"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if got, want := lines[0], "time,g.Count"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := lines[len(lines)-1], start.Add(3*time.Second).Format(time.RFC3339Nano)+",30"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if err := s.writeTrackCSV(b, "g.Other"); err == nil {
+		t.Error("error expected for path that is not tracked")
+	}
+}
+
+func TestServeTrack(t *testing.T) {
+	g := &trackedGauges{Buckets: map[string]uint{"a": 1}}
+	s := NewService("g", g).(*service)
+	s.explorer.options.TrackInterval = time.Hour
+	s.ExplorePath("g.Buckets")
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"action":"track","row":0,"column":1,"selections":["a"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"action":"trackPath","selections":["g.Count"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := len(s.tracks), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := s.tracks[0].expr, `g.Buckets["a"]`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `<table class="tracks">`) {
+		t.Error("tracks panel expected")
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/?csv=g.Count", nil)
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Header().Get("content-type"), "text/csv"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"action":"trackPath","selections":["g.Name"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusBadRequest; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
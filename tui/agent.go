@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Operations of the agent protocol, see structexplorer.AgentHandler.
+const (
+	opRoots    = "roots"
+	opFields   = "fields"
+	opNavigate = "navigate"
+)
+
+type (
+	request struct {
+		Op        string `json:"op"`
+		Path      string `json:"path,omitempty"`
+		Key       string `json:"key,omitempty"`
+		HideZeros bool   `json:"hideZeros,omitempty"`
+		SortBy    string `json:"sortBy,omitempty"`
+		Filter    string `json:"filter,omitempty"`
+	}
+	response struct {
+		Roots []root `json:"roots,omitempty"`
+		Node  *node  `json:"node,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+	root struct {
+		Label string `json:"label"`
+		Type  string `json:"type"`
+	}
+	node struct {
+		Label      string  `json:"label"`
+		Path       string  `json:"path"`
+		Type       string  `json:"type"`
+		Length     int     `json:"length,omitempty"`
+		HasZeros   bool    `json:"hasZeros,omitempty"`
+		TotalCount int     `json:"totalCount"`
+		Fields     []field `json:"fields"`
+	}
+	field struct {
+		Label string `json:"label"`
+		Key   string `json:"key"`
+		Path  string `json:"path"`
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+)
+
+// client sends requests of the agent protocol.
+type client interface {
+	do(req request) (response, error)
+}
+
+// handlerClient calls the agent handler of a service in the same process.
+type handlerClient struct {
+	handler http.Handler
+}
+
+func (c handlerClient) do(req request) (response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return response{}, err
+	}
+	r, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return response{}, err
+	}
+	w := &bufferWriter{header: http.Header{}, code: http.StatusOK}
+	c.handler.ServeHTTP(w, r)
+	return decodeResponse(w.code, w.body.Bytes())
+}
+
+// bufferWriter is a http.ResponseWriter that keeps the response in memory.
+type bufferWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *bufferWriter) Header() http.Header         { return w.header }
+func (w *bufferWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *bufferWriter) WriteHeader(code int)        { w.code = code }
+
+// httpClient posts requests to an agent listening at a URL, see structexplorer.StartAgent.
+type httpClient struct {
+	url string
+}
+
+func (c httpClient) do(req request) (response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return response{}, err
+	}
+	resp, err := http.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return response{}, err
+	}
+	defer resp.Body.Close()
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return response{}, err
+	}
+	return decodeResponse(resp.StatusCode, buf.Bytes())
+}
+
+// decodeResponse returns the response of the agent or the error it reported.
+func decodeResponse(code int, body []byte) (resp response, err error) {
+	if code != http.StatusOK {
+		return resp, fmt.Errorf("agent responded %d: %s", code, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return resp, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,417 @@
+// Package tui explores the values of a structexplorer.Service, or of an agent, in a terminal.
+// The fields of one value are listed at a time, the same way as in a cell of the page:
+// with ranges for large slices and maps, sorting, filtering and hiding of zero values.
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emicklei/structexplorer"
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+const help = "↑↓ move  enter open  ← back  z zeros  s sort  / filter  r refresh  q quit"
+
+// sortOrders is the cycle of the s key; empty is the order of the page.
+var sortOrders = []string{"", "key", "value", "type"}
+
+// minDetailWidth is the width of the terminal from which the selected field is also shown in a pane.
+const minDetailWidth = 60
+
+// Start explores the values of the service in the terminal until q is pressed.
+func Start(s structexplorer.Service) error {
+	return start(handlerClient{handler: s.AgentHandler()})
+}
+
+// StartAgent explores the values of an agent in the terminal until q is pressed.
+// The URL is that of the agent, e.g. http://localhost:5656/, see structexplorer.StartAgent.
+func StartAgent(url string) error {
+	return start(httpClient{url: url})
+}
+
+func start(c client) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	defer screen.Fini()
+	a := newApp(screen, c)
+	if err := a.refresh(); err != nil {
+		return err
+	}
+	for {
+		a.draw()
+		switch ev := screen.PollEvent().(type) {
+		case nil:
+			return nil
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if !a.handle(ev) {
+				return nil
+			}
+		}
+	}
+}
+
+// view is one listed value; the first view of an app lists the roots.
+type view struct {
+	node      *node // nil for the roots
+	roots     []root
+	cursor    int // index of the selected entry
+	offset    int // index of the first entry shown
+	hideZeros bool
+	sortBy    string
+	filter    string
+}
+
+// entry is a listed root or field.
+type entry struct {
+	label, key, path, typ, value string
+}
+
+func (v *view) entries() (list []entry) {
+	if v.node == nil {
+		for _, each := range v.roots {
+			list = append(list, entry{label: each.Label, key: each.Label, path: each.Label, typ: each.Type, value: each.Type})
+		}
+		return
+	}
+	for _, each := range v.node.Fields {
+		list = append(list, entry{label: each.Label, key: each.Key, path: each.Path, typ: each.Type, value: each.Value})
+	}
+	return
+}
+
+// move changes the selected entry by delta, within the entries.
+func (v *view) move(delta int) {
+	v.cursor = max(0, min(v.cursor+delta, len(v.entries())-1))
+}
+
+// scroll changes the offset such that the selected entry is one of the height entries shown.
+func (v *view) scroll(height int) {
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+height {
+		v.offset = v.cursor - height + 1
+	}
+}
+
+// app navigates from the roots to the values of their fields.
+type app struct {
+	screen  tcell.Screen
+	client  client
+	stack   []*view // the last is shown
+	message string  // error of the last key, shown in the footer
+	editing bool    // true while typing a filter
+	input   string  // filter being typed
+}
+
+func newApp(screen tcell.Screen, c client) *app {
+	return &app{screen: screen, client: c, stack: []*view{{}}}
+}
+
+func (a *app) current() *view {
+	return a.stack[len(a.stack)-1]
+}
+
+// handle performs the action of the key and returns false to quit.
+func (a *app) handle(ev *tcell.EventKey) bool {
+	if a.editing {
+		a.handleFilterKey(ev)
+		return true
+	}
+	a.message = ""
+	v := a.current()
+	var err error
+	switch ev.Key() {
+	case tcell.KeyCtrlC:
+		return false
+	case tcell.KeyUp:
+		v.move(-1)
+	case tcell.KeyDown:
+		v.move(1)
+	case tcell.KeyPgUp:
+		v.move(-a.listHeight())
+	case tcell.KeyPgDn:
+		v.move(a.listHeight())
+	case tcell.KeyHome:
+		v.cursor = 0
+	case tcell.KeyEnd:
+		v.move(len(v.entries()))
+	case tcell.KeyEnter, tcell.KeyRight:
+		err = a.open()
+	case tcell.KeyLeft, tcell.KeyBackspace, tcell.KeyBackspace2:
+		err = a.back()
+	case tcell.KeyEscape:
+		if v.filter != "" {
+			v.filter = ""
+			err = a.refresh()
+		}
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			return false
+		case 'k':
+			v.move(-1)
+		case 'j':
+			v.move(1)
+		case 'l':
+			err = a.open()
+		case 'h':
+			err = a.back()
+		case 'r':
+			err = a.refresh()
+		case 'z':
+			if v.node != nil {
+				v.hideZeros = !v.hideZeros
+				err = a.refresh()
+			}
+		case 's':
+			if v.node != nil {
+				v.sortBy = nextSortOrder(v.sortBy)
+				err = a.refresh()
+			}
+		case '/':
+			if v.node != nil {
+				a.editing = true
+				a.input = v.filter
+			}
+		}
+	}
+	if err != nil {
+		a.message = err.Error()
+	}
+	return true
+}
+
+// handleFilterKey edits the filter; enter applies it and escape stops editing without changes.
+func (a *app) handleFilterKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		a.editing = false
+		v := a.current()
+		previous := v.filter
+		v.filter = a.input
+		if err := a.refresh(); err != nil {
+			v.filter = previous
+			a.message = err.Error()
+		}
+	case tcell.KeyEscape:
+		a.editing = false
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if runes := []rune(a.input); len(runes) > 0 {
+			a.input = string(runes[:len(runes)-1])
+		}
+	case tcell.KeyRune:
+		a.input += string(ev.Rune())
+	}
+}
+
+func nextSortOrder(sortBy string) string {
+	for i, each := range sortOrders {
+		if each == sortBy {
+			return sortOrders[(i+1)%len(sortOrders)]
+		}
+	}
+	return sortOrders[0]
+}
+
+// open shows the value of the selected entry.
+func (a *app) open() error {
+	v := a.current()
+	list := v.entries()
+	if len(list) == 0 {
+		return nil
+	}
+	selected := list[v.cursor]
+	req := request{Op: opFields, Path: selected.key, HideZeros: true}
+	if v.node != nil {
+		req = request{Op: opNavigate, Path: v.node.Path, Key: selected.key, HideZeros: true}
+	}
+	resp, err := a.client.do(req)
+	if err != nil {
+		return err
+	}
+	next := &view{node: resp.Node, hideZeros: true}
+	a.stack = append(a.stack, next)
+	if len(next.node.Fields) == 0 && next.node.HasZeros {
+		// only zero values, same as on the page
+		next.hideZeros = false
+		return a.refresh()
+	}
+	return nil
+}
+
+// back shows the previous value again, with its current fields.
+func (a *app) back() error {
+	if len(a.stack) == 1 {
+		return nil
+	}
+	a.stack = a.stack[:len(a.stack)-1]
+	return a.refresh()
+}
+
+// refresh requests the shown value again, e.g. after changing how its fields are listed.
+func (a *app) refresh() error {
+	v := a.current()
+	if v.node == nil {
+		resp, err := a.client.do(request{Op: opRoots})
+		if err != nil {
+			return err
+		}
+		v.roots = resp.Roots
+	} else {
+		resp, err := a.client.do(request{Op: opFields, Path: v.node.Path, HideZeros: v.hideZeros, SortBy: v.sortBy, Filter: v.filter})
+		if err != nil {
+			return err
+		}
+		v.node = resp.Node
+	}
+	v.move(0)
+	return nil
+}
+
+// listHeight is the number of entries shown, between the header and the footer.
+func (a *app) listHeight() int {
+	_, h := a.screen.Size()
+	return max(h-2, 1)
+}
+
+func (a *app) draw() {
+	s := a.screen
+	s.Clear()
+	s.HideCursor()
+	w, h := s.Size()
+	v := a.current()
+	list := v.entries()
+	a.drawText(0, 0, w, tcell.StyleDefault.Reverse(true), runewidth.FillRight(a.header(), w))
+
+	height := a.listHeight()
+	v.scroll(height)
+	listWidth := w
+	if w >= minDetailWidth {
+		listWidth = w * 3 / 5
+	}
+	labelWidth := 0
+	for _, each := range list {
+		labelWidth = max(labelWidth, runewidth.StringWidth(each.label))
+	}
+	labelWidth = min(labelWidth, listWidth/2)
+	for i := 0; i < height && v.offset+i < len(list); i++ {
+		each := list[v.offset+i]
+		style := tcell.StyleDefault
+		if v.offset+i == v.cursor {
+			style = style.Reverse(true)
+		}
+		line := runewidth.FillRight(runewidth.Truncate(each.label, labelWidth, "…"), labelWidth) + " : " + each.value
+		a.drawText(0, 1+i, listWidth, style, runewidth.FillRight(line, listWidth))
+	}
+	if len(list) == 0 {
+		empty := "no fields"
+		if v.node == nil {
+			empty = "no values explored"
+		}
+		a.drawText(0, 1, listWidth, tcell.StyleDefault.Dim(true), empty)
+	}
+	if listWidth < w && len(list) > 0 {
+		a.drawDetail(listWidth, w-listWidth, height, list[v.cursor])
+	}
+
+	switch {
+	case a.editing:
+		a.drawText(0, h-1, w, tcell.StyleDefault, "/"+a.input)
+		s.ShowCursor(1+runewidth.StringWidth(a.input), h-1)
+	case a.message != "":
+		a.drawText(0, h-1, w, tcell.StyleDefault.Foreground(tcell.ColorRed), a.message)
+	default:
+		a.drawText(0, h-1, w, tcell.StyleDefault.Dim(true), help)
+	}
+	s.Show()
+}
+
+// header returns the path and type of the shown value and how its fields are listed.
+func (a *app) header() string {
+	v := a.current()
+	if v.node == nil {
+		return fmt.Sprintf("roots (%d)", len(v.roots))
+	}
+	header := v.node.Path + "  " + v.node.Type
+	if v.node.Length > 0 {
+		header += fmt.Sprintf(" (%d)", v.node.Length)
+	}
+	var notes []string
+	if v.hideZeros && v.node.HasZeros {
+		notes = append(notes, "zeros hidden")
+	}
+	if v.sortBy != "" {
+		notes = append(notes, "sorted by "+v.sortBy)
+	}
+	if v.filter != "" {
+		notes = append(notes, fmt.Sprintf("filter %s %d/%d", v.filter, len(v.node.Fields), v.node.TotalCount))
+	}
+	if len(notes) > 0 {
+		header += "  [" + strings.Join(notes, ", ") + "]"
+	}
+	return header
+}
+
+// drawDetail shows the path, type and complete value of the selected entry in a pane on the right.
+func (a *app) drawDetail(x, width, height int, selected entry) {
+	for y := 1; y <= height; y++ {
+		a.screen.SetContent(x, y, tcell.RuneVLine, nil, tcell.StyleDefault.Dim(true))
+	}
+	x, width = x+2, width-2
+	var lines []string
+	lines = append(lines, wrap("path: "+selected.path, width)...)
+	lines = append(lines, wrap("type: "+selected.typ, width)...)
+	lines = append(lines, "")
+	lines = append(lines, wrap(selected.value, width)...)
+	for i := 0; i < height && i < len(lines); i++ {
+		a.drawText(x, 1+i, width, tcell.StyleDefault, lines[i])
+	}
+}
+
+// drawText writes the text on one line starting at x, up to width cells.
+func (a *app) drawText(x, y, width int, style tcell.Style, text string) {
+	end := x + width
+	for _, r := range text {
+		if r < ' ' {
+			r = ' '
+		}
+		rw := runewidth.RuneWidth(r)
+		if x+rw > end {
+			return
+		}
+		a.screen.SetContent(x, y, r, nil, style)
+		x += rw
+	}
+}
+
+// wrap splits the text into lines of at most width cells.
+func wrap(text string, width int) (lines []string) {
+	if width < 1 {
+		return
+	}
+	for _, each := range strings.Split(text, "\n") {
+		line, lineWidth := new(strings.Builder), 0
+		for _, r := range each {
+			rw := runewidth.RuneWidth(r)
+			if lineWidth+rw > width {
+				lines = append(lines, line.String())
+				line.Reset()
+				lineWidth = 0
+			}
+			line.WriteRune(r)
+			lineWidth += rw
+		}
+		lines = append(lines, line.String())
+	}
+	return
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/structexplorer"
+	"github.com/gdamore/tcell/v2"
+)
+
+type shop struct {
+	Name   string
+	Stock  map[string]int
+	Orders []int
+	Closed bool
+}
+
+func newTestApp(t *testing.T, s structexplorer.Service, width, height int) *app {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(screen.Fini)
+	screen.SetSize(width, height)
+	a := newApp(screen, handlerClient{handler: s.AgentHandler()})
+	if err := a.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	a.draw()
+	return a
+}
+
+// press handles the keys, given as runes or names of special keys, and returns the screen.
+func press(a *app, keys ...string) string {
+	for _, each := range keys {
+		var ev *tcell.EventKey
+		switch each {
+		case "enter":
+			ev = tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+		case "esc":
+			ev = tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+		case "left":
+			ev = tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone)
+		case "down":
+			ev = tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case "end":
+			ev = tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+		default:
+			ev = tcell.NewEventKey(tcell.KeyRune, []rune(each)[0], tcell.ModNone)
+		}
+		a.handle(ev)
+		a.draw()
+	}
+	return screenText(a.screen.(tcell.SimulationScreen))
+}
+
+func screenText(screen tcell.SimulationScreen) string {
+	cells, width, height := screen.GetContents()
+	b := new(strings.Builder)
+	for y := 0; y < height; y++ {
+		line := new(strings.Builder)
+		for x := 0; x < width; x++ {
+			if runes := cells[y*width+x].Runes; len(runes) > 0 {
+				line.WriteRune(runes[0])
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func assertScreen(t *testing.T, screen string, lines ...string) {
+	t.Helper()
+	for _, each := range lines {
+		if !strings.Contains(screen, each) {
+			t.Errorf("missing %q in\n%s", each, screen)
+		}
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	value := &shop{Name: "corner", Stock: map[string]int{"apple": 3, "pear": 0}, Orders: make([]int, 120)}
+	value.Orders[75] = 9
+	a := newTestApp(t, structexplorer.NewService("shop", value), 100, 12)
+	assertScreen(t, press(a), "roots (1)\n", "shop : *tui.shop")
+
+	assertScreen(t, press(a, "enter"),
+		"shop  *tui.shop  [zeros hidden]\n",
+		"Name   : \"corner\"",
+		"path: shop.Name",
+		"type: string")
+
+	assertScreen(t, press(a, "down", "enter"),
+		"shop.Stock  map[string]int (2)  [zeros hidden]\n",
+		"\"apple\" : 3")
+	assertScreen(t, press(a, "z"),
+		"shop.Stock  map[string]int (2)\n",
+		"\"pear\"  : 0")
+
+	// a range of a large slice and an element of it
+	screen := press(a, "left", "down", "enter", "down", "enter")
+	assertScreen(t, screen, "75 : 9")
+	if got, want := a.current().node.Fields[0].Key, "75"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	assertScreen(t, press(a, "enter"), "cannot explore 75")
+
+	if a.handle(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone)) {
+		t.Error("must quit on q")
+	}
+}
+
+func TestSortAndFilter(t *testing.T) {
+	value := &shop{Stock: map[string]int{"apple": 3, "pear": 1, "fig": 2}}
+	a := newTestApp(t, structexplorer.NewService("shop", value), 100, 12)
+	press(a, "enter", "enter")
+
+	assertScreen(t, press(a, "s", "s"), "[sorted by value]\n")
+	if got, want := listedLabels(a), `"pear","fig","apple"`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	assertScreen(t, press(a, "/", "p"), "\n/p\n")
+	assertScreen(t, press(a, "enter"), "[sorted by value, filter p 2/3]\n")
+	if got, want := listedLabels(a), `"pear","apple"`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	assertScreen(t, press(a, "esc"), "[sorted by value]\n")
+
+	// an invalid regular expression keeps the filter
+	screen := press(a, "/", "/", "[", "/", "enter")
+	if got, want := a.current().filter, ""; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	assertScreen(t, screen, "error parsing regexp")
+}
+
+func listedLabels(a *app) string {
+	var labels []string
+	for _, each := range a.current().entries() {
+		labels = append(labels, each.label)
+	}
+	return strings.Join(labels, ",")
+}
+
+func TestNarrowScreen(t *testing.T) {
+	value := &shop{Name: strings.Repeat("x", 100)}
+	a := newTestApp(t, structexplorer.NewService("shop", value), 40, 6)
+	screen := press(a, "enter", "end")
+	if strings.Contains(screen, "path:") {
+		t.Errorf("no detail pane expected in\n%s", screen)
+	}
+	assertScreen(t, screen, "Name   : \"xxxxxxxxxxxxxxxxxxxxxxxxxxxxxx\n")
+}
+
+func TestAgentClient(t *testing.T) {
+	server := httptest.NewServer(structexplorer.NewService("shop", &shop{Name: "corner"}).AgentHandler())
+	defer server.Close()
+	c := httpClient{url: server.URL}
+	resp, err := c.do(request{Op: opFields, Path: "shop", HideZeros: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resp.Node.Fields[0].Value, `"corner"`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if _, err := c.do(request{Op: opFields, Path: "other"}); err == nil {
+		t.Error("error expected")
+	}
+}
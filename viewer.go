@@ -0,0 +1,104 @@
+package structexplorer
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//go:embed viewer_tmpl.html
+var viewerHTML string
+
+//go:embed viewer.js
+var viewerJS string
+
+var viewerTemplate = template.Must(template.New("viewer").Parse(viewerHTML))
+
+// maxAgentRequestSize is the maximum size of a request that is forwarded to an agent.
+const maxAgentRequestSize = 1 << 20
+
+type viewerPageData struct {
+	Script template.JS
+	Style  template.CSS
+	Agents []string
+}
+
+// viewer serves a page that renders the values of agents; requests of the page are forwarded to them.
+type viewer struct {
+	agents []string
+	client *http.Client
+}
+
+// NewViewer returns an HTTP Handler with a page to explore the values of one or more agents.
+// Each agent is the URL at which its AgentHandler is served, e.g. "http://localhost:5657/".
+func NewViewer(agentURLs ...string) http.Handler {
+	return &viewer{agents: agentURLs, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// ServeHTTP implements http.Handler
+func (v *viewer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		v.servePage(w)
+	case http.MethodPost:
+		v.forward(w, r)
+	default:
+		http.Error(w, "GET or POST", http.StatusMethodNotAllowed)
+	}
+}
+
+func (v *viewer) servePage(w http.ResponseWriter) {
+	data := viewerPageData{
+		Script: template.JS(viewerJS),
+		Style:  template.CSS(styleCSS),
+		Agents: v.agents,
+	}
+	w.Header().Set("content-type", "text/html")
+	if err := viewerTemplate.Execute(w, data); err != nil {
+		slog.Error("failed to execute template", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// forward posts the JSON request to the agent with the index of the "agent" query parameter.
+func (v *viewer) forward(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.URL.Query().Get("agent"))
+	if err != nil || index < 0 || index >= len(v.agents) {
+		http.Error(w, "invalid agent", http.StatusBadRequest)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAgentRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := v.client.Post(v.agents[index], "application/json", bytes.NewReader(body))
+	if err != nil {
+		v.writeError(w, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		v.writeError(w, fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(message)))
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		slog.Error("[structexplorer] failed to forward agent response", "agent", v.agents[index], "err", err)
+	}
+}
+
+// writeError writes a response with the error such that the page shows it as an error of the agent.
+func (v *viewer) writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusBadGateway)
+	json.NewEncoder(w).Encode(agentResponse{Error: err.Error()})
+}
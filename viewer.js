@@ -0,0 +1,260 @@
+// Renders the values of agents; agents is set by the page, see viewer.go.
+// Each cell shows the fields of a value of one agent, which are requested again on refresh.
+let cells = [];
+// whether an agent allows changing values, by index; see Options.AllowEdit.
+let editable = [];
+
+// post a request to the agent with the index, see agent.go for the operations.
+async function request(agent, body) {
+    try {
+        const response = await fetch("?agent=" + agent, {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify(body),
+        });
+        return await response.json();
+    } catch (error) {
+        return { error: String(error) };
+    }
+}
+
+// place the roots of all agents in the first column.
+async function startViewer() {
+    document.getElementById("agents").textContent = agents.join(" · ");
+    let row = 0;
+    for (let agent = 0; agent < agents.length; agent++) {
+        const response = await request(agent, { op: "roots" });
+        if (response.error) {
+            showMessage(agents[agent] + ": " + response.error);
+            continue;
+        }
+        editable[agent] = response.editable === true;
+        for (const root of response.roots || []) {
+            cells.push({ row: row++, column: 0, agent: agent, path: root.label, isRoot: true, hideZeros: true });
+        }
+    }
+    await refresh();
+}
+
+// request the fields of all cells again.
+async function refresh() {
+    await Promise.all(cells.map(loadCell));
+    renderGrid();
+}
+
+async function loadCell(cell) {
+    const response = await request(cell.agent, {
+        op: "fields",
+        path: cell.path,
+        hideZeros: cell.hideZeros,
+        sortBy: cell.sortBy,
+        filter: cell.filter,
+    });
+    cell.error = response.error;
+    if (response.node) cell.node = response.node;
+}
+
+function showMessage(text) {
+    document.getElementById("message").textContent = text || "";
+}
+
+function cellAt(row, column) {
+    return cells.find((c) => c.row == row && c.column == column);
+}
+
+// put the cell at the location or, if taken, the next free column on that row.
+function placeCell(row, column, cell) {
+    while (cellAt(row, column) != null) {
+        column++;
+    }
+    cell.row = row;
+    cell.column = column;
+    cells.push(cell);
+}
+
+// action is one of "down", "right", "up", "toggleZeros" or "remove"
+async function exploreViewer(row, column, action) {
+    showMessage("");
+    const cell = cellAt(row, column);
+    const select = document.getElementById("id" + row + "-" + column);
+    switch (action) {
+        case "toggleZeros":
+            cell.hideZeros = !cell.hideZeros;
+            await loadCell(cell);
+            break;
+        case "remove":
+            cells = cells.filter((c) => c != cell);
+            break;
+        default:
+            let toRow = row;
+            let toColumn = column;
+            if (action == "down") toRow++;
+            if (action == "right") toColumn++;
+            if (action == "up") toRow = Math.max(0, toRow - 1);
+            for (const key of getSelectValues(select)) {
+                const response = await request(cell.agent, { op: "navigate", path: cell.node.path, key: key, hideZeros: true });
+                if (response.error) {
+                    showMessage(response.error);
+                    continue;
+                }
+                placeCell(toRow, toColumn, { agent: cell.agent, path: response.node.path, node: response.node, hideZeros: true });
+            }
+    }
+    renderGrid();
+}
+
+// set the sort or filter of a cell and request its fields again.
+async function setViewerOption(row, column, option, value) {
+    const cell = cellAt(row, column);
+    cell[option] = value;
+    await loadCell(cell);
+    renderGrid();
+}
+
+// change the value of the selected field, then request all cells again.
+async function editViewer(row, column) {
+    const cell = cellAt(row, column);
+    const keys = getSelectValues(document.getElementById("id" + row + "-" + column));
+    const field = cell.node.fields.find((f) => f.key == keys[0]);
+    if (keys.length != 1 || field == null) {
+        showMessage("select one field to change");
+        return;
+    }
+    const text = prompt("new value of " + field.path + " (" + field.type + ")", unquote(field.value));
+    if (text == null) return;
+    const response = await request(cell.agent, { op: "edit", path: field.path, value: text });
+    showMessage(response.error);
+    await refresh();
+}
+
+// unquote returns the string of a quoted value, such that it can be changed as text.
+function unquote(value) {
+    if (value.startsWith('"')) {
+        try {
+            return JSON.parse(value);
+        } catch (e) {}
+    }
+    return value;
+}
+
+function renderGrid() {
+    const grid = document.getElementById("grid");
+    grid.replaceChildren();
+    const rows = Math.max(0, ...cells.map((c) => c.row + 1));
+    for (let row = 0; row < rows; row++) {
+        const tr = grid.insertRow();
+        const columns = Math.max(0, ...cells.filter((c) => c.row == row).map((c) => c.column + 1));
+        for (let column = 0; column < columns; column++) {
+            const td = tr.insertCell();
+            const cell = cellAt(row, column);
+            if (cell != null) {
+                td.appendChild(renderCell(cell));
+            }
+        }
+    }
+}
+
+function renderCell(cell) {
+    const col = element("div", "col");
+    col.appendChild(element("div", "agent", agents[cell.agent]));
+    const node = cell.node;
+    if (node == null) {
+        col.appendChild(element("div", "path", cell.path));
+        col.appendChild(element("div", "error", cell.error));
+        return col;
+    }
+    const path = element("div", "path", node.label);
+    path.title = node.path;
+    col.appendChild(path);
+    col.appendChild(element("div", "typename", node.type + (node.length ? " (" + node.length + ")" : "")));
+    if (cell.error) {
+        col.appendChild(element("div", "error", cell.error));
+    }
+    const select = element("select");
+    select.id = "id" + cell.row + "-" + cell.column;
+    select.multiple = true;
+    const width = Math.max(0, ...node.fields.map((f) => f.label.length));
+    for (const field of node.fields) {
+        const option = element("option", "", field.label.padEnd(width, " ") + ": " + field.value);
+        option.value = field.key;
+        option.title = field.label + " : " + field.type;
+        select.appendChild(option);
+    }
+    select.size = Math.max(1, node.fields.length);
+    col.appendChild(select);
+    if (node.totalCount > 1 || cell.filter) {
+        col.appendChild(renderControls(cell));
+    }
+    const bar = element("div", "buttonbar");
+    bar.appendChild(button("⇊", "explore all selected in the row below", () => exploreViewer(cell.row, cell.column, "down")));
+    bar.appendChild(button("⇉", "explore all selected in columns on the right", () => exploreViewer(cell.row, cell.column, "right")));
+    bar.appendChild(button("⇈", "explore all selected in the row above", () => exploreViewer(cell.row, cell.column, "up")));
+    if (node.hasZeros) {
+        bar.appendChild(button("z", "hide or show fields with zero values", () => exploreViewer(cell.row, cell.column, "toggleZeros")));
+    }
+    if (editable[cell.agent]) {
+        bar.appendChild(button("✎", "change the value of the selected field", () => editViewer(cell.row, cell.column)));
+    }
+    if (!cell.isRoot) {
+        bar.appendChild(button("x", "remove the object from this page", () => exploreViewer(cell.row, cell.column, "remove")));
+    }
+    col.appendChild(bar);
+    return col;
+}
+
+function renderControls(cell) {
+    const controls = element("div", "cellcontrols");
+    const sort = element("select");
+    sort.title = "sort the entries";
+    for (const by of ["", "key", "value", "type"]) {
+        const option = element("option", "", "⇅ " + (by || "default"));
+        option.value = by;
+        option.selected = (cell.sortBy || "") == by;
+        sort.appendChild(option);
+    }
+    sort.onchange = () => setViewerOption(cell.row, cell.column, "sortBy", sort.value);
+    controls.appendChild(sort);
+    const filter = element("input");
+    filter.type = "text";
+    filter.size = 10;
+    filter.value = cell.filter || "";
+    filter.placeholder = "filter";
+    filter.title = "show entries with key or value containing this text or matching /regexp/";
+    filter.onkeydown = (event) => {
+        if (event.key === "Enter") setViewerOption(cell.row, cell.column, "filter", filter.value);
+    };
+    controls.appendChild(filter);
+    if (cell.filter) {
+        const count = element("span", "", cell.node.fields.length + "/" + cell.node.totalCount);
+        count.title = "entries matching the filter";
+        controls.appendChild(count);
+    }
+    return controls;
+}
+
+function element(tag, className, text) {
+    const node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text != null) node.textContent = text;
+    return node;
+}
+
+function button(text, title, onclick) {
+    const node = element("button", "btn", text);
+    node.title = title;
+    node.onclick = onclick;
+    return node;
+}
+
+// Return an array of the selected option values in the control.
+// Select is an HTML select element.
+function getSelectValues(select) {
+    const result = [];
+    for (const option of select.options) {
+        if (option.selected) result.push(option.value);
+    }
+    if (result.length == 0 && select.options.length == 1) {
+        result.push(select.options[0].value);
+    }
+    return result;
+}
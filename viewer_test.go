@@ -0,0 +1,48 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestViewer(t *testing.T) {
+	agent := httptest.NewServer(NewService("server", &editedServer{Name: "api"}).AgentHandler())
+	defer agent.Close()
+	v := NewViewer(agent.URL, "http://localhost:1")
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	v.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), "startViewer()") || !strings.Contains(rec.Body.String(), agent.URL) {
+		t.Errorf("unexpected page %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/?agent=0", strings.NewReader(`{"op":"fields","path":"server"}`))
+	v.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !strings.Contains(rec.Body.String(), `\"api\"`) {
+		t.Errorf("unexpected response %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/?agent=1", strings.NewReader(`{"op":"roots"}`))
+	v.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusBadGateway; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("unexpected response %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/?agent=2", strings.NewReader(`{"op":"roots"}`))
+	v.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusBadRequest; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+package structexplorer
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// watch is a path expression that is evaluated each time the page is built.
+type watch struct {
+	label     string
+	expr      string
+	value     string    // of the last evaluation
+	changedAt time.Time // when value was different from the previous evaluation
+}
+
+// Watch adds or replaces (matching on label) a path expression that is shown, above the explored values,
+// with its current value and the time it last changed.
+// The path may refer to values that are explored later.
+func (s *service) Watch(label, path string) Service {
+	defer s.protect()()
+	s.watch(label, path)
+	return s
+}
+
+// pre: protected
+func (s *service) watch(label, path string) {
+	if label == "" {
+		label = path
+	}
+	for _, each := range s.watches {
+		if each.label == label {
+			each.expr = path
+			each.changedAt = time.Time{}
+			return
+		}
+	}
+	s.watches = append(s.watches, &watch{label: label, expr: path})
+}
+
+// pre: protected
+func (s *service) unwatch(label string) {
+	for i, each := range s.watches {
+		if each.label == label {
+			s.watches = append(s.watches[:i], s.watches[i+1:]...)
+			return
+		}
+	}
+}
+
+// watchEntries evaluates all watches and returns them in order of registration.
+// pre: protected
+func (s *service) watchEntries(now time.Time) (list []watchEntry) {
+	for _, each := range s.watches {
+		value, err := s.explorer.valueStringForPath(each.expr)
+		if err != nil {
+			slog.Debug("[structexplorer] cannot evaluate watch", "label", each.label, "err", err)
+			value = "⚠ " + err.Error()
+		}
+		if value != each.value || each.changedAt.IsZero() {
+			each.value = value
+			each.changedAt = now
+		}
+		list = append(list, watchEntry{
+			Label:        each.label,
+			Path:         each.expr,
+			Value:        each.value,
+			IsError:      err != nil,
+			ChangedAt:    each.changedAt.Format(time.TimeOnly),
+			ChangedSince: now.Sub(each.changedAt).Round(time.Second).String(),
+		})
+	}
+	return
+}
+
+// valueStringForPath returns the display value of the value at the path expression.
+// pre: protected
+func (e *explorer) valueStringForPath(expr string) (string, error) {
+	parsed, err := parsePath(expr, e.rootLabels())
+	if err != nil {
+		return "", err
+	}
+	root, _, _, _ := e.rootAccessWithLabel(parsed.root)
+	defer root.lock()()
+	v, err := resolveAccessPath(root.object, parsed.keys)
+	if err != nil {
+		return "", err
+	}
+	return safePrintString(v), nil
+}
+
+// safePrintString returns the display value, or its type if computing it panics.
+func safePrintString(v any) (s string) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Warn("[structexplorer] failed to get display value, fallback display", "type", fmt.Sprintf("%T", v), "err", err)
+			s = ellipsis(fallbackPrintString(v))
+		}
+	}()
+	return ellipsis(printString(v))
+}
@@ -0,0 +1,86 @@
+package structexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type watchedGame struct {
+	Players map[string]*watchedPlayer
+}
+
+type watchedPlayer struct {
+	Score int
+}
+
+func TestWatchEntries(t *testing.T) {
+	game := &watchedGame{Players: map[string]*watchedPlayer{"alice": {Score: 1}}}
+	s := NewService("game", game).(*service)
+	s.Watch("alice", `game.Players["alice"].Score`)
+	s.Watch("", `game.Players["bob"].Score`)
+
+	start := time.Now()
+	list := s.watchEntries(start)
+	if got, want := len(list), 2; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[0].Value, "1"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[1].Label, `game.Players["bob"].Score`; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if !list[1].IsError {
+		t.Error("error expected for missing key")
+	}
+
+	// unchanged
+	list = s.watchEntries(start.Add(time.Minute))
+	if got, want := list[0].ChangedSince, "1m0s"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// changed
+	game.Players["alice"].Score = 2
+	game.Players["bob"] = &watchedPlayer{Score: 3}
+	list = s.watchEntries(start.Add(2 * time.Minute))
+	if got, want := list[0].Value+list[1].Value, "23"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := list[0].ChangedSince, "0s"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	s.unwatch("alice")
+	if got, want := len(s.watchEntries(start)), 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestServeWatch(t *testing.T) {
+	game := &watchedGame{Players: map[string]*watchedPlayer{"alice": {Score: 42}}}
+	s := NewService("game", game).(*service)
+
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(`{"action":"watch","selections":["game.Players[\"alice\"].Score"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `<table class="watches">`) {
+		t.Error("watch panel expected")
+	}
+
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/", strings.NewReader(`{"action":"watch","selections":["other.Score"]}`))
+	s.ServeHTTP(rec, req)
+	if got, want := rec.Code, http.StatusBadRequest; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
@@ -0,0 +1,222 @@
+package structexplorer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// defaultWorkspace is the name of the workspace that is shared by all browsers without a workspace of their own.
+// Values explored from code, using ExplorePath, are put in this workspace.
+const defaultWorkspace = "default"
+
+// workspaceCookieName is the name of the cookie that holds the name of the workspace selected in a browser.
+const workspaceCookieName = "structexplorer-workspace"
+
+// sessionCookieName is the name of the cookie that identifies a browser, to give it a workspace of its own.
+const sessionCookieName = "structexplorer-session"
+
+// maxWorkspaces is the maximum number of workspaces other than the default.
+const maxWorkspaces = 16
+
+// newWorkspace returns an explorer with the same roots, mutex and options.
+func (e *explorer) newWorkspace() *explorer {
+	w := &explorer{
+		accessMap: map[int]map[int]objectAccess{},
+		options:   e.options,
+		mutex:     e.mutex,
+	}
+	for row, each := range e.accessMap {
+		for col, access := range each {
+			if access.isRoot {
+				w.putObjectAt(row, col, access)
+			}
+		}
+	}
+	return w
+}
+
+// cloneWorkspace returns an explorer with the same cells, mutex and options.
+func (e *explorer) cloneWorkspace() *explorer {
+	w := &explorer{
+		accessMap: map[int]map[int]objectAccess{},
+		options:   e.options,
+		mutex:     e.mutex,
+	}
+	for row, each := range e.accessMap {
+		for col, access := range each {
+			w.putObjectAt(row, col, access)
+		}
+	}
+	return w
+}
+
+// hasOnlyRoots returns true if no values were explored from the roots.
+func (e *explorer) hasOnlyRoots() bool {
+	for _, each := range e.accessMap {
+		for _, access := range each {
+			if !access.isRoot {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// workspaceFor returns the name and explorer of the workspace for the request; it does not create one.
+// The "workspace" query parameter takes precedence over the workspace cookie and then the session cookie.
+// A name without a workspace is served by the default workspace.
+// pre: protected
+func (s *service) workspaceFor(r *http.Request) (string, *explorer) {
+	name := s.workspaceName(r)
+	if e, ok := s.workspaces[name]; ok {
+		return name, e
+	}
+	return defaultWorkspace, s.explorer
+}
+
+// workspaceName returns the name of the workspace requested or selected by the browser,
+// or the name of the workspace of its own.
+// pre: protected
+func (s *service) workspaceName(r *http.Request) string {
+	if name := r.URL.Query().Get("workspace"); name != "" {
+		return name
+	}
+	if c, err := r.Cookie(workspaceCookieName); err == nil {
+		if name, _ := url.QueryUnescape(c.Value); name != "" {
+			return name
+		}
+	}
+	if id := sessionID(r); id != "" && id != s.defaultOwner {
+		return sessionWorkspace(id)
+	}
+	return defaultWorkspace
+}
+
+// workspaceForChange returns the name and explorer of the workspace for a request that changes the layout.
+// The first browser that changes the layout uses the default workspace; every other browser gets
+// a workspace of its own, starting with the cells of the default workspace.
+// pre: protected
+func (s *service) workspaceForChange(r *http.Request) (string, *explorer) {
+	id := sessionID(r)
+	name := s.workspaceName(r)
+	if _, ok := s.workspaces[name]; ok || id == "" || name != sessionWorkspace(id) {
+		return s.workspaceFor(r)
+	}
+	if s.defaultOwner == "" {
+		s.defaultOwner = id
+		return defaultWorkspace, s.explorer
+	}
+	if err := s.addWorkspace(name, s.explorer.cloneWorkspace()); err != nil {
+		slog.Warn("[structexplorer] using the default workspace", "err", err)
+	}
+	return s.workspaceFor(r)
+}
+
+// addWorkspace adds a workspace unless there are too many.
+// To make room, workspaces with only roots are removed, oldest first and those of browsers before named ones.
+// pre: protected
+func (s *service) addWorkspace(name string, e *explorer) error {
+	for _, each := range s.evictableWorkspaces() {
+		if len(s.workspaces) < maxWorkspaces {
+			break
+		}
+		s.removeWorkspace(each)
+	}
+	if len(s.workspaces) >= maxWorkspaces {
+		return errors.New("too many workspaces, clear one to make room")
+	}
+	s.workspaces[name] = e
+	s.workspaceAge = append(s.workspaceAge, name)
+	return nil
+}
+
+// evictableWorkspaces returns the names of the workspaces with only roots,
+// those of browsers first, oldest first.
+// pre: protected
+func (s *service) evictableWorkspaces() (list []string) {
+	named := []string{}
+	for _, each := range s.workspaceAge {
+		if !s.workspaces[each].hasOnlyRoots() {
+			continue
+		}
+		if strings.HasPrefix(each, sessionWorkspace("")) {
+			list = append(list, each)
+		} else {
+			named = append(named, each)
+		}
+	}
+	return append(list, named...)
+}
+
+// removeWorkspace removes the workspace with the name.
+// pre: protected
+func (s *service) removeWorkspace(name string) {
+	delete(s.workspaces, name)
+	s.workspaceAge = slices.DeleteFunc(s.workspaceAge, func(each string) bool { return each == name })
+}
+
+// serveNewWorkspace adds a workspace with the roots for the name, the only selection, unless it exists.
+// pre: protected
+func (s *service) serveNewWorkspace(w http.ResponseWriter, selections []string) {
+	if len(selections) != 1 || selections[0] == "" || selections[0] == defaultWorkspace {
+		http.Error(w, "[structexplorer] invalid workspace name", http.StatusBadRequest)
+		return
+	}
+	name := selections[0]
+	if _, ok := s.workspaces[name]; ok {
+		return
+	}
+	if err := s.addWorkspace(name, s.explorer.newWorkspace()); err != nil {
+		http.Error(w, "[structexplorer] "+err.Error(), http.StatusBadRequest)
+	}
+}
+
+// sessionID returns the value of the session cookie, or empty if the browser has none.
+func sessionID(r *http.Request) string {
+	if c, err := r.Cookie(sessionCookieName); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// sessionWorkspace returns the name of the workspace of the browser with the session id.
+func sessionWorkspace(id string) string {
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return "browser-" + id
+}
+
+// newSessionID returns a random value for the session cookie.
+func newSessionID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// workspaceNames returns the default name followed by the other names sorted.
+// pre: protected
+func (s *service) workspaceNames() []string {
+	list := []string{}
+	for name := range s.workspaces {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return append([]string{defaultWorkspace}, list...)
+}
+
+// eachWorkspace calls the function for the default and all other workspaces.
+// pre: protected
+func (s *service) eachWorkspace(fn func(e *explorer)) {
+	fn(s.explorer)
+	for _, each := range s.workspaces {
+		fn(each)
+	}
+}
@@ -0,0 +1,139 @@
+package structexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postInstruction(s *service, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	for _, each := range cookies {
+		req.AddCookie(each)
+	}
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func cookieNamed(rec *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, each := range rec.Result().Cookies() {
+		if each.Name == name {
+			return each
+		}
+	}
+	return nil
+}
+
+func TestWorkspaces(t *testing.T) {
+	s := NewService("now", time.Now()).(*service)
+
+	// a link to an unknown workspace does not create it
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/?workspace=alice", nil)
+	s.ServeHTTP(rec, req)
+	if cookieNamed(rec, workspaceCookieName) != nil {
+		t.Error("unexpected workspace cookie")
+	}
+	if got, want := len(s.workspaces), 0; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// create and switch to workspace
+	if got, want := postInstruction(s, `{"action":"newWorkspace","selections":["alice"]}`).Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	rec = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/?workspace=alice", nil)
+	s.ServeHTTP(rec, req)
+	cookie := cookieNamed(rec, workspaceCookieName)
+	if cookie == nil {
+		t.Fatal("missing workspace cookie")
+	}
+
+	// explore in workspace
+	postInstruction(s, `{"row":0,"column":0,"action":"down","selections":["loc"]}`, cookie)
+	if got, want := len(s.workspaces["alice"].accessMap[1]), 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := len(s.explorer.accessMap[1]), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+
+	// roots are shared
+	s.Explore("later", time.Now())
+	if _, _, _, ok := s.workspaces["alice"].rootAccessWithLabel("later"); !ok {
+		t.Error("missing root in workspace")
+	}
+	if got, want := strings.Join(s.workspaceNames(), ","), "default,alice"; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestSessionWorkspaces(t *testing.T) {
+	s := NewService("now", time.Now()).(*service)
+	rec := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	s.ServeHTTP(rec, req)
+	if cookieNamed(rec, sessionCookieName) == nil {
+		t.Fatal("missing session cookie")
+	}
+
+	// the first browser uses the default workspace
+	first := &http.Cookie{Name: sessionCookieName, Value: "11111111aa"}
+	postInstruction(s, `{"row":0,"column":0,"action":"down","selections":["loc"]}`, first)
+	if got, want := len(s.explorer.accessMap[1]), 1; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	// another browser gets a copy
+	second := &http.Cookie{Name: sessionCookieName, Value: "22222222bb"}
+	postInstruction(s, `{"row":1,"column":0,"action":"remove"}`, second)
+	own, ok := s.workspaces["browser-22222222"]
+	if !ok {
+		t.Fatalf("missing workspace in %v", s.workspaceNames())
+	}
+	if got, want := len(own.accessMap[1]), 0; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := len(s.explorer.accessMap[1]), 1; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+}
+
+func TestWorkspacesLimit(t *testing.T) {
+	s := NewService("now", time.Now()).(*service)
+	for i := 0; i < maxWorkspaces; i++ {
+		postInstruction(s, fmt.Sprintf(`{"action":"newWorkspace","selections":["w%d"]}`, i))
+	}
+	s.workspaces["w0"].explorePath("now.loc")
+	// the oldest workspace with only roots makes room
+	if got, want := postInstruction(s, `{"action":"newWorkspace","selections":["extra"]}`).Code, http.StatusOK; got != want {
+		t.Fatalf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	if got, want := len(s.workspaces), maxWorkspaces; got != want {
+		t.Errorf("got [%[1]v:%[1]T] want [%[2]v:%[2]T]", got, want)
+	}
+	for _, each := range []string{"w0", "w2", "extra"} {
+		if _, ok := s.workspaces[each]; !ok {
+			t.Errorf("missing workspace %s", each)
+		}
+	}
+	if _, ok := s.workspaces["w1"]; ok {
+		t.Error("workspace w1 must be removed")
+	}
+	// workspaces of browsers make room before named ones
+	s.removeWorkspace("w15")
+	s.addWorkspace(sessionWorkspace("0123456789"), s.explorer.newWorkspace())
+	if err := s.addWorkspace("latest", s.explorer.newWorkspace()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.workspaces[sessionWorkspace("0123456789")]; ok {
+		t.Error("workspace of browser must be removed")
+	}
+	if _, ok := s.workspaces["w2"]; !ok {
+		t.Error("missing workspace w2")
+	}
+}